@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+)
+
+// maxAnalysisSamples is the maximum number of samples in each direction to analyze a frame.
+// Frames are analyzed on a downscaled grid, as exact values are not needed.
+const maxAnalysisSamples = 64
+
+// forEachSample calls f with the YCbCr values of the sampled pixels of the frame.
+func forEachSample(frame image.Image, f func(y, cb, cr uint8)) {
+	var img *image.YCbCr
+	switch frame := frame.(type) {
+	case *image.YCbCr:
+		img = frame
+	case *image.NYCbCrA:
+		img = &frame.YCbCr
+	default:
+		return
+	}
+
+	b := img.Rect
+	stepX := max((b.Dx()+maxAnalysisSamples-1)/maxAnalysisSamples, 1)
+	stepY := max((b.Dy()+maxAnalysisSamples-1)/maxAnalysisSamples, 1)
+	for j := b.Min.Y + stepY/2; j < b.Max.Y; j += stepY {
+		for i := b.Min.X + stepX/2; i < b.Max.X; i += stepX {
+			c := img.YCbCrAt(i, j)
+			f(c.Y, c.Cb, c.Cr)
+		}
+	}
+}
+
+func clampUint8(v float64) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
+
+// dominantColor returns the dominant color of the frame.
+//
+// The sampled colors are quantized into coarse buckets, and the average color of the most populated bucket is returned.
+// This is cheaper than k-means, and is stable enough for ambient lighting.
+func dominantColor(frame image.Image, cs colorSpace) color.RGBA {
+	type bucket struct {
+		r, g, b int
+		count   int
+	}
+	var buckets [512]bucket
+
+	m := cs.rgbMatrix()
+	forEachSample(frame, func(y, cb, cr uint8) {
+		r, g, b := m.toRGB(y, cb, cr)
+		bk := &buckets[int(r>>5)<<6|int(g>>5)<<3|int(b>>5)]
+		bk.r += int(r)
+		bk.g += int(g)
+		bk.b += int(b)
+		bk.count++
+	})
+
+	var best *bucket
+	for i := range buckets {
+		if best == nil || buckets[i].count > best.count {
+			best = &buckets[i]
+		}
+	}
+	if best.count == 0 {
+		return color.RGBA{A: 0xff}
+	}
+	return color.RGBA{
+		R: uint8(best.r / best.count),
+		G: uint8(best.g / best.count),
+		B: uint8(best.b / best.count),
+		A: 0xff,
+	}
+}
+
+// LumaHistogram represents the luma distribution of a video frame.
+//
+// LumaHistogram is computed on a downscaled frame, so the counts are not the exact numbers of pixels.
+type LumaHistogram struct {
+	// Bins is the number of samples for each luma value.
+	// The luma values are usually in [16, 235] for limited range videos, which most videos are.
+	Bins [256]int
+
+	// Samples is the total number of samples.
+	Samples int
+
+	// Mean is the mean luma, normalized so that 0 is black and 1 is white.
+	Mean float64
+}
+
+func lumaHistogram(frame image.Image, cs colorSpace) *LumaHistogram {
+	h := &LumaHistogram{}
+	var sum int
+	forEachSample(frame, func(y, cb, cr uint8) {
+		h.Bins[y]++
+		h.Samples++
+		sum += int(y)
+	})
+	if h.Samples > 0 {
+		mean := float64(sum) / float64(h.Samples)
+		if cs.fullRange {
+			h.Mean = mean / 255
+		} else {
+			h.Mean = min(max((mean-16)/(235-16), 0), 1)
+		}
+	}
+	return h
+}
+
+// downscaledLuma appends the sampled luma values of the frame to dst.
+func downscaledLuma(frame image.Image, dst []uint8) []uint8 {
+	forEachSample(frame, func(y, cb, cr uint8) {
+		dst = append(dst, y)
+	})
+	return dst
+}
+
+// meanAbsDiff returns the mean absolute difference of a and b in [0, 1].
+// a and b must have the same length.
+func meanAbsDiff(a, b []uint8) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+	var sum int
+	for i := range a {
+		d := int(a[i]) - int(b[i])
+		if d < 0 {
+			d = -d
+		}
+		sum += d
+	}
+	return float64(sum) / float64(len(a)) / 255
+}
+
+// sameFrame reports whether the two frames have exactly the same planes.
+func sameFrame(a, b image.Image) bool {
+	switch a := a.(type) {
+	case *image.YCbCr:
+		b, ok := b.(*image.YCbCr)
+		return ok && sameYCbCr(a, b)
+	case *image.NYCbCrA:
+		b, ok := b.(*image.NYCbCrA)
+		return ok && sameYCbCr(&a.YCbCr, &b.YCbCr) && a.AStride == b.AStride && bytes.Equal(a.A, b.A)
+	}
+	return false
+}
+
+func sameYCbCr(a, b *image.YCbCr) bool {
+	if a.Rect != b.Rect || a.SubsampleRatio != b.SubsampleRatio || a.YStride != b.YStride || a.CStride != b.CStride {
+		return false
+	}
+	return bytes.Equal(a.Y, b.Y) && bytes.Equal(a.Cb, b.Cb) && bytes.Equal(a.Cr, b.Cr)
+}
@@ -4,250 +4,358 @@
 package webmplayer
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 
-	"github.com/ebml-go/webm"
-
-	"github.com/hajimehoshi/webmplayer/internal/libopus"
-	"github.com/hajimehoshi/webmplayer/internal/libvorbis"
+	"github.com/hajimehoshi/webmplayer/internal/allocstats"
+	"github.com/hajimehoshi/webmplayer/internal/codec"
 )
 
 const samplesPerBuffer = 1024
 
 type audioStream struct {
-	codec             audioCodec
-	channels          int
-	samplingFrequency int
+	decoder *codec.AudioDecoder
 
-	src     <-chan webm.Packet
-	packets []webm.Packet
+	src     <-chan packet
+	packets []packet
 
-	// voInfo must be kept as voDPS has a reference to it.
-	voInfo  *libvorbis.Info
-	voDSP   *libvorbis.DspState
-	voBlock *libvorbis.Block
+	frames []float32
 
-	opDecoder *libopus.Decoder
-	opPCM     []float32
+	// gain is the linear gain applied to the output in math.Float32bits.
+	gain atomic.Uint32
 
-	frames []float32
-}
+	// pos is the current position in bytes, reported by Seek.
+	pos int64
 
-type audioCodec string
+	// epoch is the latest epoch of the stream. Packets with an older epoch are discarded.
+	epoch       atomic.Int64
+	framesEpoch int64
 
-const (
-	audioCodecVorbis audioCodec = "A_VORBIS"
-	audioCodecOpus   audioCodec = "A_OPUS"
-)
+	// discardUntil is the position until which decoded samples are discarded after seeking, or -1.
+	discardUntil time.Duration
 
-func newAudioDecoder(codec audioCodec, codecPrivate []byte, channels, samplingFrequency int, src <-chan webm.Packet) (*audioStream, error) {
-	a := &audioStream{
-		channels:          channels,
-		samplingFrequency: samplingFrequency,
-		codec:             codec,
-		src:               src,
-	}
-	// TODO: Clear vo* and op* objects explicitly when a is finalized.
-	switch codec {
-	case audioCodecVorbis:
-		info, _, err := readVorbisCodecPrivate(codecPrivate)
-		if err != nil {
-			return nil, err
-		}
-		a.voInfo = info
+	finished atomic.Bool
 
-		if info.Channels() != channels {
-			a.channels = int(channels)
-			return nil, fmt.Errorf("webmplayer: channel count doesn't match: %d vs %d", info.Channels(), channels)
-		}
-		if info.Rate() != samplingFrequency {
-			a.samplingFrequency = info.Rate()
-			return nil, fmt.Errorf("webmplayer: sample rate doesn't match: %d vs %d", info.Rate(), samplingFrequency)
-		}
+	// closed indicates whether the decoder is closed.
+	closed bool
 
-		dsp, err := libvorbis.SynthesisInit(info)
-		if err != nil {
-			return nil, fmt.Errorf("webmplayer: libvorbis.SynthesisInit failed: %w", err)
-		}
-		a.voDSP = dsp
+	// decodedEnd is the media position right after the last decoded sample.
+	decodedEnd atomic.Int64
 
-		block, err := libvorbis.BlockInit(a.voDSP)
-		if err != nil {
-			return nil, fmt.Errorf("webmplayer: libvorbis.BlockInit failed: %w", err)
-		}
-		a.voBlock = block
+	// endAtEOF indicates whether Read returns io.EOF after a short tail of silence at the end of the stream, instead of returning silence forever.
+	endAtEOF bool
+	// tail is the number of bytes of the silence returned after the end of the stream.
+	tail int64
 
-		return a, nil
+	// playing indicates whether samples have been read since the start or the latest seek.
+	playing bool
+	stalls  stallCounter
 
-	case audioCodecOpus:
-		var err error
-		a.opDecoder, err = libopus.DecoderCreate(samplingFrequency, channels)
-		if err != nil {
-			return nil, err
-		}
-		a.opPCM = make([]float32, samplesPerBuffer*channels)
-		return a, nil
-	default:
-		return a, fmt.Errorf("webmplayer: unsupported audio codec: %s", codec)
+	m sync.Mutex
+}
+
+// newAudioStream creates an audio stream to read the samples decoded from the packets of src.
+func newAudioStream(decoder *codec.AudioDecoder, src <-chan packet) *audioStream {
+	a := &audioStream{
+		decoder:      decoder,
+		src:          src,
+		discardUntil: -1,
 	}
+	a.gain.Store(math.Float32bits(1))
+	return a
 }
 
 func (a *audioStream) Read(buf []byte) (int, error) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	if a.closed {
+		return 0, io.EOF
+	}
+
+	a.discardStaleData()
+
 readFrames:
 	if len(a.frames) > 0 {
-		n := copy(unsafe.Slice((*float32)(unsafe.Pointer(unsafe.SliceData(buf))), len(buf)/4), a.frames)
+		dst := unsafe.Slice((*float32)(unsafe.Pointer(unsafe.SliceData(buf))), len(buf)/4)
+		n := copy(dst, a.frames)
+		if gain := math.Float32frombits(a.gain.Load()); gain != 1 {
+			for i := range dst[:n] {
+				dst[i] *= gain
+			}
+		}
 		a.frames = a.frames[n:]
+		a.pos += 4 * int64(n)
+		a.playing = true
 		return 4 * n, nil
 	}
 
 	for len(a.packets) == 0 {
-		pkt, ok := <-a.src
-		if !ok {
-			n := min(len(buf)/4*4, 256)
-			for i := range n {
-				buf[i] = 0
+		var pkt packet
+		var ok bool
+		if a.finished.Load() {
+			// Do not block after the end of the stream, as there might be no more packets unless seeking.
+			select {
+			case pkt, ok = <-a.src:
+			default:
+				return a.readEnd(buf)
 			}
-			return n, nil
+		} else {
+			// Waiting for packets after the playback starts means that the packets are not demuxed in time.
+			pkt, ok = a.stalls.receive(a.src, a.playing && a.framesEpoch == a.epoch.Load())
 		}
-		if len(pkt.Data) == 0 {
-			continue
+		if !ok {
+			a.finished.Store(true)
+			return a.readEnd(buf)
+		}
+		if err := a.handlePacket(pkt); err != nil {
+			return 0, err
 		}
-		a.packets = append(a.packets, pkt)
 	}
 
 	pkt := a.packets[0]
 	a.packets = a.packets[1:]
 
-	switch a.codec {
-	case audioCodecVorbis:
-		packet := &libvorbis.OggPacket{
-			Packet: pkt.Data,
-		}
-		if err := libvorbis.Synthesis(a.voBlock, packet); err != nil {
-			return 0, fmt.Errorf("webmplayer: libvorbis.Synthesis failed: %w", err)
-		}
+	_, err := a.decodePacket(pkt)
+	pkt.Release()
+	if err != nil {
+		return 0, err
+	}
+
+	goto readFrames
+}
+
+// Preload decodes packets in advance until at least d of samples are buffered.
+//
+// Preload returns without an error when the stream ends, or stalled returns true.
+func (a *audioStream) Preload(ctx context.Context, d time.Duration, stalled func() bool) error {
+	a.m.Lock()
+	defer a.m.Unlock()
 
-		if err := libvorbis.SynthesisBlockin(a.voDSP, a.voBlock); err != nil {
-			return 0, fmt.Errorf("webmplayer: libvorbis.SynthesisBlockin failed: %w", err)
+	if a.closed {
+		return nil
+	}
+
+	a.discardStaleData()
+
+	n := 2 * int(int64(d)*int64(a.SamplingFrequency())/int64(time.Second))
+
+	t := time.NewTicker(10 * time.Millisecond)
+	defer t.Stop()
+
+	for len(a.frames) < n && !a.finished.Load() {
+		if len(a.packets) > 0 {
+			pkt := a.packets[0]
+			a.packets = a.packets[1:]
+			_, err := a.decodePacket(pkt)
+			pkt.Release()
+			if err != nil {
+				return err
+			}
+			continue
 		}
 
-		for pcm := libvorbis.SynthesisPcmout(a.voDSP); len(pcm) > 0 && len(pcm[0]) > 0; pcm = libvorbis.SynthesisPcmout(a.voDSP) {
-			switch a.channels {
-			case 1:
-				for i := range pcm[0] {
-					v := pcm[0][i]
-					a.frames = append(a.frames, v, v)
-				}
-			case 2:
-				for i := range pcm[0] {
-					for ch := range pcm {
-						v := pcm[ch][i]
-						a.frames = append(a.frames, v)
-					}
-				}
-			default:
-				return 0, fmt.Errorf("webmplayer: unsupported channel count: %d", a.channels)
+		select {
+		case pkt, ok := <-a.src:
+			if !ok {
+				a.finished.Store(true)
+				return nil
+			}
+			if err := a.handlePacket(pkt); err != nil {
+				return err
 			}
-			if err := libvorbis.SynthesisRead(a.voDSP, len(pcm[0])); err != nil {
-				return 0, fmt.Errorf("webmplayer: libvorbis.SynthesisRead failed: %w", err)
+		case <-t.C:
+			if stalled != nil && stalled() {
+				return nil
 			}
+		case <-ctx.Done():
+			return ctx.Err()
 		}
+	}
+	return nil
+}
 
-		goto readFrames
+// discardStaleData discards the data decoded before the latest seek.
+func (a *audioStream) discardStaleData() {
+	if epoch := a.epoch.Load(); a.framesEpoch < epoch {
+		a.frames = a.frames[:0]
+		for i := range a.packets {
+			a.packets[i].Release()
+		}
+		a.packets = a.packets[:0]
+	}
+}
 
-	case audioCodecOpus:
-		sampleCount := a.opDecoder.DecodeFloat(pkt.Data, a.opPCM, 0)
-		if sampleCount <= 0 {
-			return 0, nil
+// handlePacket handles a packet sent from the stream.
+// A packet with data is appended to a.packets, and is released after it is decoded.
+func (a *audioStream) handlePacket(pkt packet) error {
+	if pkt.epoch < a.epoch.Load() {
+		pkt.Release()
+		return nil
+	}
+	if pkt.seek {
+		if err := a.reset(pkt.Timecode); err != nil {
+			return err
 		}
+		a.framesEpoch = pkt.epoch
+		a.playing = false
+		a.tail = 0
+		a.discardUntil = pkt.Timecode
+		a.decodedEnd.Store(int64(pkt.Timecode))
+		return nil
+	}
+	if pkt.eos {
+		a.finished.Store(true)
+		return pkt.err
+	}
+	if len(pkt.Data) == 0 {
+		return nil
+	}
+	a.packets = append(a.packets, pkt)
+	return nil
+}
 
-		origLen := len(a.frames)
-		a.frames = append(a.frames, a.opPCM[:int(sampleCount)*a.channels]...)
-		if a.channels == 1 {
-			a.frames = append(a.frames, make([]float32, sampleCount)...)
-			frames := a.frames[origLen:]
-			for i := int(sampleCount) - 1; i > 0; i-- {
-				frames[2*i] = frames[i]
-				frames[2*i+1] = frames[i]
+// decodePacket decodes the packet and appends the decoded stereo samples to a.frames.
+// decodePacket returns the timecode of the first decoded sample.
+func (a *audioStream) decodePacket(pkt packet) (time.Duration, error) {
+	origLen := len(a.frames)
+	if allocstats.Enabled {
+		origCap := cap(a.frames)
+		defer func() {
+			if cap(a.frames) != origCap {
+				allocstats.Add(allocstats.AudioDecode, 4*cap(a.frames))
 			}
-		}
+		}()
+	}
 
-		goto readFrames
+	frames, tc, err := a.decoder.Decode(a.frames, pkt.Data, pkt.Timecode, pkt.DiscardPadding)
+	a.frames = frames
+	if err != nil {
+		return 0, err
+	}
 
-	default:
-		return 0, fmt.Errorf("webmplayer: unsupported audio codec: %s", a.codec)
+	sampleCount := (len(a.frames) - origLen) / 2
+	if sampleCount > 0 {
+		a.decodedEnd.Store(int64(tc + time.Duration(int64(sampleCount)*int64(time.Second)/int64(a.SamplingFrequency()))))
 	}
+
+	if a.discardUntil >= 0 {
+		// Discard the samples before the seek target.
+		n := int(int64(a.discardUntil-tc) * int64(a.SamplingFrequency()) / int64(time.Second))
+		if n >= sampleCount {
+			a.frames = a.frames[:origLen]
+		} else {
+			if n > 0 {
+				a.frames = append(a.frames[:origLen], a.frames[origLen+2*n:]...)
+			}
+			a.discardUntil = -1
+		}
+	}
+
+	return tc, nil
 }
 
-func (a *audioStream) Channels() int {
-	return a.channels
+// audioEOFTail is the duration of the silence after the end of the stream before io.EOF,
+// so that the last samples are not cut by the interpolation of the following streams.
+const audioEOFTail = 50 * time.Millisecond
+
+// readEnd reads the silence after the end of the stream, and returns io.EOF after the tail if endAtEOF is true.
+func (a *audioStream) readEnd(buf []byte) (int, error) {
+	if !a.endAtEOF {
+		return a.readSilence(buf), nil
+	}
+	size := 8 * int64(audioEOFTail) * int64(a.SamplingFrequency()) / int64(time.Second)
+	if a.tail >= size {
+		return 0, io.EOF
+	}
+	n := a.readSilence(buf[:min(int64(len(buf)), size-a.tail)])
+	a.tail += int64(n)
+	return n, nil
 }
 
-func (a *audioStream) SamplingFrequency() int {
-	return a.samplingFrequency
+func (a *audioStream) readSilence(buf []byte) int {
+	n := min(len(buf)/4*4, 256)
+	for i := range n {
+		buf[i] = 0
+	}
+	a.pos += int64(n)
+	return n
 }
 
-func readVorbisCodecPrivate(codecPrivate []byte) (*libvorbis.Info, *libvorbis.Comment, error) {
-	if len(codecPrivate) < 1 {
-		return nil, nil, errors.New("webmplayer: codec private data is too short")
+// reset resets the decoder state for seeking to the given timecode.
+func (a *audioStream) reset(timecode time.Duration) error {
+	a.frames = a.frames[:0]
+	for i := range a.packets {
+		a.packets[i].Release()
 	}
+	a.packets = a.packets[:0]
+	return a.decoder.Reset(timecode)
+}
 
-	p := codecPrivate
+func (a *audioStream) setEpoch(epoch int64) {
+	a.epoch.Store(epoch)
+	a.finished.Store(false)
+}
 
-	// https://www.matroska.org/technical/codec_specs.html
-	// > Byte 1: number of distinct packets #p minus one inside the CodecPrivate block. This MUST be “2” for current (as of 2016-07-08) Vorbis headers.
-	if p[0] != 0x02 {
-		return nil, nil, fmt.Errorf("webmplayer: wrong codec private data for Vorbis: %d", p[0])
+// Seek implements io.Seeker so that audio.Player's SetPosition can be used.
+// Seek only updates the position, and the actual seeking is done by the stream.
+func (a *audioStream) Seek(offset int64, whence int) (int64, error) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+		a.pos = offset
+	case io.SeekCurrent:
+		a.pos += offset
+	default:
+		return 0, fmt.Errorf("webmplayer: whence must be io.SeekStart or io.SeekCurrent for Seek: %d", whence)
 	}
-	offset := 1
-	p = p[1:]
+	return a.pos, nil
+}
 
-	headers := make([][]byte, 3)
-	var size0, size1 int
+// Close releases the decoder. Read returns io.EOF after Close.
+func (a *audioStream) Close() {
+	a.m.Lock()
+	defer a.m.Unlock()
 
-	// https://xiph.org/vorbis/doc/framing.html
-	// > The raw packet is logically divided into [n] 255 byte segments and a last fractional segment of < 255 bytes.
-	// > A packet size may well consist only of the trailing fractional segment, and a fractional segment may be zero length.
-	// > These values, called "lacing values" are then saved and placed into the header segment table.
-	for i := 0; i < 2; i++ {
-		for (p[0] == 0xff) && offset < len(codecPrivate) {
-			if i == 0 {
-				size0 += 0xff
-			} else {
-				size1 += 0xff
-			}
-			offset++
-			p = p[1:]
-		}
-		if offset >= len(codecPrivate)-1 {
-			return nil, nil, errors.New("webmplayer: header sizes damaged")
-		}
-		if i == 0 {
-			size0 += int(p[0])
-		} else {
-			size1 += int(p[0])
-		}
-		offset++
-		p = p[1:]
+	if a.closed {
+		return
 	}
-	headers[0] = codecPrivate[offset : offset+size0]
-	headers[1] = codecPrivate[offset+size0 : offset+size0+size1]
-	headers[2] = codecPrivate[offset+size0+size1:]
+	a.closed = true
+	for i := range a.packets {
+		a.packets[i].Release()
+	}
+	a.packets = nil
+	a.frames = nil
+	a.decoder.Close()
+}
 
-	info := libvorbis.InfoInit()
-	comment := libvorbis.CommentInit()
+// SetGain sets the linear gain applied to the output.
+func (a *audioStream) SetGain(gain float64) {
+	a.gain.Store(math.Float32bits(float32(gain)))
+}
 
-	for i := 0; i < 3; i++ {
-		packet := &libvorbis.OggPacket{
-			Packet: headers[i],
-			BOS:    i == 0,
-		}
-		if err := libvorbis.SynthesisHeaderin(info, comment, packet); err != nil {
-			return nil, nil, fmt.Errorf("webmplayer: libvorbis.SynthesisHeaderin failed: %w", err)
-		}
-	}
+// DecodedEnd returns the media position right after the last decoded sample.
+// At the end of the stream, this is the end of the audio.
+func (a *audioStream) DecodedEnd() time.Duration {
+	return time.Duration(a.decodedEnd.Load())
+}
 
-	return info, comment, nil
+func (a *audioStream) IsFinished() bool {
+	return a.finished.Load()
+}
+
+func (a *audioStream) Channels() int {
+	return a.decoder.Channels()
+}
+
+func (a *audioStream) SamplingFrequency() int {
+	return a.decoder.SamplingFrequency()
 }
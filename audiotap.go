@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"io"
+	"sync/atomic"
+	"unsafe"
+)
+
+// audioTap passes the float32 samples read from src to a callback, before they are handed to the audio player.
+type audioTap struct {
+	src io.ReadSeeker
+
+	f atomic.Pointer[func(samples []float32)]
+}
+
+func (a *audioTap) Read(buf []byte) (int, error) {
+	n, err := a.src.Read(buf)
+	if f := a.f.Load(); f != nil && n >= 4 {
+		(*f)(unsafe.Slice((*float32)(unsafe.Pointer(unsafe.SliceData(buf))), n/4))
+	}
+	return n, err
+}
+
+func (a *audioTap) Seek(offset int64, whence int) (int64, error) {
+	return a.src.Seek(offset, whence)
+}
+
+// SetCallback sets the callback. f can be nil.
+func (a *audioTap) SetCallback(f func(samples []float32)) {
+	if f == nil {
+		a.f.Store(nil)
+		return
+	}
+	a.f.Store(&f)
+}
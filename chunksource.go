@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ChunkSourceOptions represents options for NewChunkSource.
+type ChunkSourceOptions struct {
+	// MaxRetainedSize is the maximum size in bytes of the data kept after it is read, to seek backward.
+	// A limit bounds the memory usage for a live stream, which continues indefinitely.
+	// The demuxer seeks back a little to the elements it has just read, so the limit should be at least a few hundred kilobytes.
+	//
+	// The default (zero) value is no limit, which keeps all the data so that the player can seek anywhere.
+	MaxRetainedSize int
+}
+
+// ChunkSource is a stream appended in chunks, e.g. received via WebSocket or a custom transport.
+//
+// Pass a ChunkSource to New as a stream, and append the chunks with Append as they arrive.
+// The player waits for the data that has not been appended yet.
+// Call Close at the end of the stream.
+//
+// ChunkSource is goroutine-safe.
+type ChunkSource struct {
+	// data is the appended data from the position base.
+	data []byte
+	base int64
+
+	pos             int64
+	maxRetainedSize int
+	closed          bool
+
+	m    sync.Mutex
+	cond *sync.Cond
+}
+
+// NewChunkSource creates a new empty ChunkSource.
+//
+// If options is nil, the default values are used.
+func NewChunkSource(options *ChunkSourceOptions) *ChunkSource {
+	if options == nil {
+		options = &ChunkSourceOptions{}
+	}
+	c := &ChunkSource{
+		maxRetainedSize: options.MaxRetainedSize,
+	}
+	c.cond = sync.NewCond(&c.m)
+	return c
+}
+
+// Append appends data to the end of the stream.
+// data is copied, so the caller can reuse data after Append returns.
+//
+// Append after Close does nothing.
+func (c *ChunkSource) Append(data []byte) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.closed {
+		return
+	}
+	c.data = append(c.data, data...)
+	c.cond.Broadcast()
+}
+
+// Close ends the stream. Read returns io.EOF at the end of the appended data.
+//
+// Close also stops the player waiting for data.
+// Call Close when the player is closed before the end of the stream, so that the player's goroutines finish.
+func (c *ChunkSource) Close() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.closed = true
+	c.cond.Broadcast()
+	return nil
+}
+
+// Read implements io.Reader. Read blocks until data is appended at the current position or the stream is closed.
+func (c *ChunkSource) Read(buf []byte) (int, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	for {
+		if c.pos < c.base {
+			return 0, fmt.Errorf("webmplayer: the data at %d is no longer retained", c.pos)
+		}
+		if c.pos < c.base+int64(len(c.data)) {
+			n := copy(buf, c.data[c.pos-c.base:])
+			c.pos += int64(n)
+			c.discard()
+			return n, nil
+		}
+		if c.closed {
+			return 0, io.EOF
+		}
+		c.cond.Wait()
+	}
+}
+
+// Seek implements io.Seeker.
+// The position can be after the appended data, where Read waits for the data.
+// io.SeekEnd is available only after Close.
+func (c *ChunkSource) Seek(offset int64, whence int) (int64, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = c.pos + offset
+	case io.SeekEnd:
+		if !c.closed {
+			return 0, fmt.Errorf("webmplayer: the end of a ChunkSource is unknown before Close")
+		}
+		pos = c.base + int64(len(c.data)) + offset
+	default:
+		return 0, fmt.Errorf("webmplayer: invalid whence: %d", whence)
+	}
+	if pos < 0 {
+		return 0, fmt.Errorf("webmplayer: negative position: %d", pos)
+	}
+	c.pos = pos
+	return pos, nil
+}
+
+// discard drops the data read more than maxRetainedSize bytes before the current position.
+func (c *ChunkSource) discard() {
+	if c.maxRetainedSize <= 0 {
+		return
+	}
+	n := c.pos - c.base - int64(c.maxRetainedSize)
+	// Drop the data only when it is as large as the retained data, so that the copies are amortized.
+	if n < int64(c.maxRetainedSize) {
+		return
+	}
+	c.data = c.data[:copy(c.data, c.data[n:])]
+	c.base += n
+}
@@ -0,0 +1,321 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/webmplayer/internal/libvpx"
+	"github.com/hajimehoshi/webmplayer/internal/webm"
+)
+
+// colorMatrix represents matrix coefficients to convert YCbCr to RGB.
+type colorMatrix int
+
+const (
+	colorMatrixBT601 colorMatrix = iota
+	colorMatrixBT709
+	colorMatrixBT2020
+	colorMatrixSMPTE240
+	// colorMatrixIdentity represents GBR stored in the Y, Cb and Cr planes.
+	colorMatrixIdentity
+)
+
+// hdrTransfer represents the transfer function of HDR frames.
+type hdrTransfer int
+
+const (
+	hdrTransferNone hdrTransfer = iota
+	hdrTransferPQ
+	hdrTransferHLG
+)
+
+// colorSpace represents how to convert YCbCr values to RGB.
+type colorSpace struct {
+	matrix    colorMatrix
+	fullRange bool
+
+	// hdr is the transfer function of HDR frames to tone-map to SDR, or hdrTransferNone not to tone-map.
+	hdr hdrTransfer
+
+	// peak is the peak luminance of HDR frames in nits.
+	peak float64
+}
+
+// sdrWhite is the luminance of the SDR reference white in nits, as recommended by ITU-R BT.2408.
+const sdrWhite = 203
+
+// toneMapKnee is the luminance relative to the SDR reference white above which HDR luminance is compressed.
+const toneMapKnee = 0.75
+
+// resolveColorSpace determines the color space of frames.
+//
+// The container's Colour element takes precedence over the bitstream's color space.
+// If neither specifies the matrix, BT.601 is used for SD and BT.709 is used for HD, as most players do.
+//
+// If toneMap is true, HDR frames with the PQ or HLG transfer and the BT.2020 primaries are tone-mapped.
+// The bitstream doesn't have the transfer, so only the Colour element is used.
+func resolveColorSpace(colour *webm.Colour, cs libvpx.ColorSpace, r libvpx.ColorRange, height int, toneMap bool) colorSpace {
+	var c colorSpace
+
+	// https://www.matroska.org/technical/elements.html#MatrixCoefficients
+	// MatrixCoefficients is 0 only when there is no Colour element, as 0 is overwritten by the default value 2 when parsing.
+	switch colour.MatrixCoefficients {
+	case 1:
+		c.matrix = colorMatrixBT709
+	case 4, 5, 6:
+		c.matrix = colorMatrixBT601
+	case 7:
+		c.matrix = colorMatrixSMPTE240
+	case 9, 10:
+		c.matrix = colorMatrixBT2020
+	default:
+		switch cs {
+		case libvpx.ColorSpaceBt601, libvpx.ColorSpaceSmpte170:
+			c.matrix = colorMatrixBT601
+		case libvpx.ColorSpaceBt709:
+			c.matrix = colorMatrixBT709
+		case libvpx.ColorSpaceSmpte240:
+			c.matrix = colorMatrixSMPTE240
+		case libvpx.ColorSpaceBt2020:
+			c.matrix = colorMatrixBT2020
+		case libvpx.ColorSpaceSrgb:
+			c.matrix = colorMatrixIdentity
+		default:
+			if height >= 720 {
+				c.matrix = colorMatrixBT709
+			} else {
+				c.matrix = colorMatrixBT601
+			}
+		}
+	}
+
+	// https://www.matroska.org/technical/elements.html#Range
+	switch colour.Range {
+	case 1:
+		c.fullRange = false
+	case 2:
+		c.fullRange = true
+	case 3:
+		c.fullRange = c.matrix == colorMatrixIdentity
+	default:
+		c.fullRange = r == libvpx.ColorRangeFull || c.matrix == colorMatrixIdentity
+	}
+
+	// https://www.matroska.org/technical/elements.html#TransferCharacteristics
+	if toneMap && colour.Primaries == 9 {
+		switch colour.TransferCharacteristics {
+		case 16:
+			c.hdr = hdrTransferPQ
+		case 18:
+			c.hdr = hdrTransferHLG
+		}
+	}
+	if c.hdr != hdrTransferNone {
+		// HLG is relative to the display, and 1000 nits is the reference display's peak.
+		c.peak = 1000
+		if c.hdr == hdrTransferPQ {
+			if colour.MaxCLL > 0 {
+				c.peak = float64(colour.MaxCLL)
+			} else if colour.MasteringMetadata.LuminanceMax > 0 {
+				c.peak = colour.MasteringMetadata.LuminanceMax
+			}
+		}
+		c.peak = max(c.peak, sdrWhite)
+	}
+
+	return c
+}
+
+// toneMap converts the non-linear BT.2020 RGB values of an HDR pixel to the sRGB values.
+// toneMap does the same calculation as the shader.
+func (c colorSpace) toneMap(r, g, b uint8) (uint8, uint8, uint8) {
+	e := [3]float64{float64(r) / 255, float64(g) / 255, float64(b) / 255}
+
+	// Convert the values to the linear light relative to the SDR reference white.
+	var l [3]float64
+	switch c.hdr {
+	case hdrTransferPQ:
+		// SMPTE ST 2084
+		const (
+			m1 = 0.1593017578125
+			m2 = 78.84375
+			c1 = 0.8359375
+			c2 = 18.8515625
+			c3 = 18.6875
+		)
+		for i, v := range e {
+			p := math.Pow(v, 1/m2)
+			l[i] = math.Pow(max(p-c1, 0)/(c2-c3*p), 1/m1) * 10000 / sdrWhite
+		}
+	case hdrTransferHLG:
+		// ITU-R BT.2100
+		const (
+			a  = 0.17883277
+			b  = 0.28466892
+			cc = 0.55991073
+		)
+		for i, v := range e {
+			if v <= 0.5 {
+				l[i] = v * v / 3
+			} else {
+				l[i] = (math.Exp((v-cc)/a) + b) / 12
+			}
+		}
+		// Apply the OOTF with the system gamma 1.2 for the 1000 nits display.
+		ys := 0.2627*l[0] + 0.6780*l[1] + 0.0593*l[2]
+		k := math.Pow(max(ys, 1e-6), 0.2) * 1000 / sdrWhite
+		for i := range l {
+			l[i] *= k
+		}
+	}
+
+	// Keep the luminance below the knee, and compress the luminance above it with the extended Reinhard operator,
+	// which maps the peak to the SDR white.
+	if y := 0.2627*l[0] + 0.6780*l[1] + 0.0593*l[2]; y > toneMapKnee {
+		t := (y - toneMapKnee) / (1 - toneMapKnee)
+		tp := (c.peak/sdrWhite - toneMapKnee) / (1 - toneMapKnee)
+		t *= (1 + t/(tp*tp)) / (1 + t)
+		k := (toneMapKnee + (1-toneMapKnee)*t) / y
+		for i := range l {
+			l[i] *= k
+		}
+	}
+
+	// Convert BT.2020 primaries to BT.709 primaries, and apply the sRGB transfer.
+	var rgb [3]uint8
+	for i, row := range bt2020ToBT709 {
+		v := min(max(row[0]*l[0]+row[1]*l[1]+row[2]*l[2], 0), 1)
+		if v <= 0.0031308 {
+			v *= 12.92
+		} else {
+			v = 1.055*math.Pow(v, 1/2.4) - 0.055
+		}
+		rgb[i] = clampUint8(v * 255)
+	}
+	return rgb[0], rgb[1], rgb[2]
+}
+
+// bt2020ToBT709 is the matrix to convert linear RGB values with BT.2020 primaries to BT.709 primaries, in row-major order.
+var bt2020ToBT709 = [3][3]float64{
+	{1.6605, -0.5876, -0.0728},
+	{-0.1246, 1.1329, -0.0083},
+	{-0.0182, -0.1006, 1.1187},
+}
+
+// rgbMatrix is an affine matrix to convert normalized YCbCr values to RGB values in row-major order.
+type rgbMatrix [3][4]float64
+
+func (c colorSpace) rgbMatrix() rgbMatrix {
+	ky, oy := 1.0, 0.0
+	kc, oc := 1.0, 128.0/255.0
+	if !c.fullRange {
+		ky, oy = 255.0/219.0, 16.0/255.0
+		kc = 255.0 / 224.0
+	}
+
+	var coeffs [3][3]float64
+	if c.matrix == colorMatrixIdentity {
+		// G, B and R are stored in the Y, Cb and Cr planes, and have the same range as luma.
+		coeffs = [3][3]float64{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}}
+		kc, oc = ky, oy
+	} else {
+		var kr, kb float64
+		switch c.matrix {
+		case colorMatrixBT601:
+			kr, kb = 0.299, 0.114
+		case colorMatrixBT709:
+			kr, kb = 0.2126, 0.0722
+		case colorMatrixBT2020:
+			kr, kb = 0.2627, 0.0593
+		case colorMatrixSMPTE240:
+			kr, kb = 0.212, 0.087
+		}
+		kg := 1 - kr - kb
+		coeffs = [3][3]float64{
+			{1, 0, 2 * (1 - kr)},
+			{1, -2 * kb * (1 - kb) / kg, -2 * kr * (1 - kr) / kg},
+			{1, 2 * (1 - kb), 0},
+		}
+	}
+
+	var m rgbMatrix
+	for i, c := range coeffs {
+		m[i] = [4]float64{
+			c[0] * ky,
+			c[1] * kc,
+			c[2] * kc,
+			-(c[0]*ky*oy + (c[1]+c[2])*kc*oc),
+		}
+	}
+	return m
+}
+
+// uniform returns the matrix as a Kage mat4 uniform value, which is in column-major order.
+func (m *rgbMatrix) uniform() []float32 {
+	u := make([]float32, 16)
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 3; row++ {
+			u[4*col+row] = float32(m[row][col])
+		}
+	}
+	u[15] = 1
+	return u
+}
+
+func (m *rgbMatrix) toRGB(y, cb, cr uint8) (uint8, uint8, uint8) {
+	v := [4]float64{float64(y) / 255, float64(cb) / 255, float64(cr) / 255, 1}
+	var rgb [3]uint8
+	for i := range rgb {
+		var s float64
+		for j := range v {
+			s += m[i][j] * v[j]
+		}
+		rgb[i] = clampUint8(s * 255)
+	}
+	return rgb[0], rgb[1], rgb[2]
+}
+
+// toRGBA converts the frame to a new premultiplied RGBA image.
+func toRGBA(frame image.Image, cs colorSpace) *image.RGBA {
+	var img *image.YCbCr
+	var alpha *image.NYCbCrA
+	switch frame := frame.(type) {
+	case *image.YCbCr:
+		img = frame
+	case *image.NYCbCrA:
+		img = &frame.YCbCr
+		alpha = frame
+	default:
+		return nil
+	}
+
+	b := img.Rect
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	m := cs.rgbMatrix()
+	for j := b.Min.Y; j < b.Max.Y; j++ {
+		row := dst.Pix[dst.Stride*(j-b.Min.Y):]
+		for i := b.Min.X; i < b.Max.X; i++ {
+			ci := img.COffset(i, j)
+			r, g, bl := m.toRGB(img.Y[img.YOffset(i, j)], img.Cb[ci], img.Cr[ci])
+			if cs.hdr != hdrTransferNone {
+				r, g, bl = cs.toneMap(r, g, bl)
+			}
+			a := uint8(0xff)
+			if alpha != nil {
+				a = alpha.A[alpha.AOffset(i, j)]
+				r = uint8(uint16(r) * uint16(a) / 0xff)
+				g = uint8(uint16(g) * uint16(a) / 0xff)
+				bl = uint8(uint16(bl) * uint16(a) / 0xff)
+			}
+			k := 4 * (i - b.Min.X)
+			row[k] = r
+			row[k+1] = g
+			row[k+2] = bl
+			row[k+3] = a
+		}
+	}
+	return dst
+}
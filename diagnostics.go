@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// diagnostic is a kind of warnings in the diagnostics mode.
+type diagnostic int
+
+const (
+	diagnosticNoVideo diagnostic = iota
+	diagnosticNoFrame
+	diagnosticDegenerateGeoM
+	diagnosticOutsideScreen
+	diagnosticTransparent
+)
+
+// warn logs the warning if the same kind of warning has not been logged yet.
+func (p *Player) warn(d diagnostic, format string, args ...any) {
+	if _, ok := p.warned[d]; ok {
+		return
+	}
+	if p.warned == nil {
+		p.warned = map[diagnostic]struct{}{}
+	}
+	p.warned[d] = struct{}{}
+	log.Printf("webmplayer: diagnostics: %s", fmt.Sprintf(format, args...))
+}
+
+// diagnoseDraw warns if drawing the frame with the given options would draw nothing visible.
+func (p *Player) diagnoseDraw(screen *ebiten.Image, frame *ebiten.Image, geoM ebiten.GeoM, colorScale ebiten.ColorScale) {
+	w, h := float64(frame.Bounds().Dx()), float64(frame.Bounds().Dy())
+
+	var minX, minY, maxX, maxY float64
+	for i, c := range [][2]float64{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		x, y := geoM.Apply(c[0], c[1])
+		if math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0) {
+			p.warn(diagnosticDegenerateGeoM, "the GeoM of DrawOptions has a NaN or an infinite value: %s", geoM.String())
+			return
+		}
+		if i == 0 {
+			minX, minY, maxX, maxY = x, y, x, y
+			continue
+		}
+		minX, minY = min(minX, x), min(minY, y)
+		maxX, maxY = max(maxX, x), max(maxY, y)
+	}
+
+	a, b, c, d := geoM.Element(0, 0), geoM.Element(0, 1), geoM.Element(1, 0), geoM.Element(1, 1)
+	if area := math.Abs(a*d-b*c) * w * h; area < 1 {
+		p.warn(diagnosticDegenerateGeoM, "the frame is drawn in an area less than one pixel (%f), and nothing is visible; check the scale of the GeoM of DrawOptions", area)
+		return
+	}
+
+	if !image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY))).Overlaps(screen.Bounds()) {
+		p.warn(diagnosticOutsideScreen, "the frame is drawn outside the screen (%.1f, %.1f)-(%.1f, %.1f); check the translation of the GeoM of DrawOptions", minX, minY, maxX, maxY)
+	}
+
+	if colorScale.A() == 0 {
+		p.warn(diagnosticTransparent, "the alpha of the ColorScale of DrawOptions is zero, and nothing is visible")
+	}
+}
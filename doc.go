@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+// Package webmplayer plays WebM videos with Ebitengine.
+//
+// The API consists of:
+//
+//   - Player, created by New, or NewPlayerFromURL for a stream on an HTTP server, to play streams in a game. Player's Update and Draw are called from ebiten.Game's Update and Draw.
+//   - Probe, to read the information of a stream without decoding it.
+//   - Decode, DecodeAudio, FrameAt, VideoFrames and Packets, to decode or demux a stream without any clock, e.g. for tools.
+//     They are also in the media package, which doesn't depend on Ebitengine.
+//
+// Options are passed as a pointer to an options struct. A nil options means the default values,
+// and the zero value of each field is its default, so that new fields can be added without breaking the existing code.
+//
+// # Goroutines
+//
+// A Player decodes packets in its own goroutines, which never use Ebitengine's images or shaders.
+// Decoded frames are kept on the CPU side, and are uploaded to the GPU lazily when the frame is drawn,
+// i.e. in Draw, RenderTo, DrawTriangles or CurrentFrame.
+// These methods, Update, ReleaseVideoResources and Close must be called in one goroutine at a time,
+// typically the goroutine calling ebiten.Game's Update and Draw. This is compatible with Ebitengine's single-thread mode.
+// A Player panics if they are called concurrently.
+//
+// The callbacks set by the Player's Set*Callback methods are called in Update, except for the one set by SetVideoFrameCallback, which is called in the decoding goroutine
+// and must not use Ebitengine's images.
+//
+// The exported API follows semantic versioning from v1.
+// A superseded API is kept with a Deprecated comment instead of being removed.
+package webmplayer
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package main
+
+import (
+	"image"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer"
+)
+
+// bench measures decoding performance of the given files per codec.
+func bench(paths []string) error {
+	for _, path := range paths {
+		if err := benchFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func benchFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := webmplayer.Probe(f)
+	if err != nil {
+		return err
+	}
+
+	// Decode each track separately so that the measured times don't include the other track's decoding.
+	if info.VideoCodecID != "" {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		var times []time.Duration
+		var first, last time.Duration = -1, 0
+		start := time.Now()
+		prev := start
+		if err := webmplayer.Decode(f, &webmplayer.DecodeOptions{
+			VideoThreads: *flagThreads,
+			OnVideoFrame: func(frame image.Image, pts time.Duration) error {
+				now := time.Now()
+				times = append(times, now.Sub(prev))
+				prev = now
+				if first < 0 {
+					first = pts
+				}
+				last = pts
+				return nil
+			},
+		}); err != nil {
+			return err
+		}
+		reportBench(path, info.VideoCodecID, "frame", times, last-first, time.Since(start))
+	}
+
+	if info.AudioCodecID != "" {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		var times []time.Duration
+		var duration time.Duration
+		start := time.Now()
+		prev := start
+		if err := webmplayer.Decode(f, &webmplayer.DecodeOptions{
+			OnAudioSamples: func(samples []float32, samplingFrequency int, pts time.Duration) error {
+				now := time.Now()
+				times = append(times, now.Sub(prev))
+				prev = now
+				duration += time.Duration(len(samples)/2) * time.Second / time.Duration(samplingFrequency)
+				return nil
+			},
+		}); err != nil {
+			return err
+		}
+		reportBench(path, info.AudioCodecID, "packet", times, duration, time.Since(start))
+	}
+
+	return nil
+}
+
+func reportBench(path string, codecID string, unit string, times []time.Duration, mediaDuration, elapsed time.Duration) {
+	if len(times) == 0 {
+		slog.Info("Bench", "file", path, "codec", codecID, "count", 0)
+		return
+	}
+
+	var total time.Duration
+	for _, t := range times {
+		total += t
+	}
+	sorted := slices.Clone(times)
+	slices.Sort(sorted)
+	percentile := func(p int) time.Duration {
+		return sorted[(len(sorted)-1)*p/100]
+	}
+
+	var realtimeFactor float64
+	if elapsed > 0 {
+		realtimeFactor = float64(mediaDuration) / float64(elapsed)
+	}
+
+	slog.Info("Bench",
+		"file", path,
+		"codec", codecID,
+		"unit", unit,
+		"count", len(times),
+		"avg", total/time.Duration(len(times)),
+		"p50", percentile(50),
+		"p95", percentile(95),
+		"p99", percentile(99),
+		"max", sorted[len(sorted)-1],
+		"mediaDuration", mediaDuration,
+		"elapsed", elapsed,
+		"realtimeFactor", realtimeFactor)
+}
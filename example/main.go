@@ -15,6 +15,16 @@ import (
 	"github.com/hajimehoshi/webmplayer"
 )
 
+var (
+	flagLoop      = flag.Int("loop", 0, "number of times to play the input again after it ends (negative means forever)")
+	flagExitOnEnd = flag.Bool("exit-on-end", false, "exit when the playback ends")
+	flagVerify    = flag.Bool("verify", false, "decode the inputs as fast as possible and report errors without playing")
+	flagBench     = flag.Bool("bench", false, "measure the decoding performance of the inputs without playing")
+	flagThreads   = flag.Int("video-threads", 0, "number of threads to decode video (0 means the decoder's default)")
+	flagRate      = flag.Float64("rate", 1, "playback rate, with the pitch of the audio preserved")
+	flagTTY       = flag.String("tty", "", `render the video in the terminal instead of a window: "ansi" for half blocks with 24-bit colors, or "sixel"`)
+)
+
 func main() {
 	flag.Parse()
 	if err := xmain(); err != nil {
@@ -24,23 +34,54 @@ func main() {
 }
 
 func xmain() error {
+	if *flagVerify {
+		return verify(flag.Args())
+	}
+	if *flagBench {
+		return bench(flag.Args())
+	}
+
 	streams := make([]io.ReadSeeker, 0, 2)
+	var paths []string
 	for _, opt := range flag.Args() {
 		f, err := os.Open(opt)
 		if err != nil {
 			return err
 		}
 		streams = append(streams, f)
+		paths = append(paths, opt)
 		if len(streams) >= 2 {
 			break
 		}
 	}
 
-	player, err := webmplayer.NewPlayer(streams...)
+	options := &webmplayer.PlayerOptions{
+		VideoThreads: *flagThreads,
+	}
+	var sink *ttySink
+	switch *flagTTY {
+	case "":
+	case "ansi", "sixel":
+		sink = newTTYSink(*flagTTY == "sixel")
+		options.FrameSink = sink
+	default:
+		return fmt.Errorf("unknown -tty mode: %q", *flagTTY)
+	}
+
+	player, err := webmplayer.New(options, streams...)
 	if err != nil {
 		return err
 	}
 
+	player.SetLoopCount(*flagLoop)
+	if *flagRate != 1 {
+		if err := player.SetPlaybackRate(*flagRate, &webmplayer.PlaybackRateOptions{
+			PreservePitch: true,
+		}); err != nil {
+			return err
+		}
+	}
+
 	if player.VideoCodecID() != "" {
 		w, h := player.VideoSize()
 		slog.Info("Video",
@@ -57,9 +98,24 @@ func xmain() error {
 			"duration", player.AudioDuration())
 	}
 
+	if player.VideoCodecID() == "" && player.AudioCodecID() != "" {
+		// Show a VU meter in the terminal instead of a blank window.
+		path, err := audioPath(paths)
+		if err != nil {
+			return err
+		}
+		return runMeter(player, path, *flagExitOnEnd)
+	}
+	if sink != nil {
+		return runTTY(player, sink, *flagExitOnEnd)
+	}
+
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetWindowTitle("WebM Player")
-	game := NewGame(player)
+	if w, h := player.VideoSize(); w > 0 && h > 0 {
+		ebiten.SetWindowSize(windowSize(w, h))
+	}
+	game := NewGame(player, *flagExitOnEnd)
 	if err := ebiten.RunGame(game); err != nil {
 		return err
 	}
@@ -68,17 +124,25 @@ func xmain() error {
 }
 
 type Game struct {
-	player *webmplayer.Player
+	player    *webmplayer.Player
+	exitOnEnd bool
 }
 
-func NewGame(p *webmplayer.Player) *Game {
+func NewGame(p *webmplayer.Player, exitOnEnd bool) *Game {
 	return &Game{
-		player: p,
+		player:    p,
+		exitOnEnd: exitOnEnd,
 	}
 }
 
 func (g *Game) Update() error {
-	return g.player.Update()
+	if err := g.player.Update(); err != nil {
+		return err
+	}
+	if g.exitOnEnd && g.player.IsFinished() {
+		return ebiten.Termination
+	}
+	return nil
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
@@ -87,12 +151,33 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		return
 	}
 
-	op := &webmplayer.PlayerDrawOptions{}
+	op := &webmplayer.DrawOptions{}
 	scale := min(float64(screen.Bounds().Dx())/float64(w), float64(screen.Bounds().Dy())/float64(h))
 	op.GeoM.Scale(scale, scale)
 	g.player.Draw(screen, op)
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return outsideWidth, outsideHeight
+	// Render in device pixels so that the video is not blurry on high-DPI displays.
+	s := ebiten.Monitor().DeviceScaleFactor()
+	return int(float64(outsideWidth) * s), int(float64(outsideHeight) * s)
+}
+
+// windowSize returns the window size in device-independent pixels to show the video of the given display size,
+// where one video pixel is one device pixel if the video fits in the monitor.
+func windowSize(videoWidth, videoHeight int) (int, int) {
+	m := ebiten.Monitor()
+	s := m.DeviceScaleFactor()
+	w, h := float64(videoWidth)/s, float64(videoHeight)/s
+
+	// Leave a margin for the window decorations and the task bar.
+	mw, mh := m.Size()
+	if mw > 0 && mh > 0 {
+		maxW, maxH := 0.9*float64(mw), 0.9*float64(mh)
+		if scale := min(maxW/w, maxH/h); scale < 1 {
+			w *= scale
+			h *= scale
+		}
+	}
+	return max(int(w), 1), max(int(h), 1)
 }
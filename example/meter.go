@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer"
+)
+
+// meterWindow is the duration of audio for one level of the VU meter.
+const meterWindow = 50 * time.Millisecond
+
+// meterLevels is the peak levels of an audio stream per meterWindow, analyzed in the background.
+type meterLevels struct {
+	// peaks is the interleaved left and right peak levels.
+	peaks []float32
+
+	m sync.Mutex
+}
+
+// analyze decodes the audio of the file at path and appends the peak levels until the end or ctx is done.
+func (l *meterLevels) analyze(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var left, right float32
+	var n, windowSize int
+	return webmplayer.Decode(f, &webmplayer.DecodeOptions{
+		OnAudioSamples: func(samples []float32, samplingFrequency int, pts time.Duration) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			windowSize = int(int64(samplingFrequency) * int64(meterWindow) / int64(time.Second))
+			for i := 0; i < len(samples); i += 2 {
+				left = max(left, float32(math.Abs(float64(samples[i]))))
+				right = max(right, float32(math.Abs(float64(samples[i+1]))))
+				n++
+				if n < windowSize {
+					continue
+				}
+				l.m.Lock()
+				l.peaks = append(l.peaks, left, right)
+				l.m.Unlock()
+				left, right, n = 0, 0, 0
+			}
+			return nil
+		},
+		OnError: func(err error) error {
+			// Skip a corrupt packet as the player does.
+			return nil
+		},
+	})
+}
+
+// at returns the peak levels at the position, or zeros if the position is not analyzed yet.
+func (l *meterLevels) at(position time.Duration) (float32, float32) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	i := int(position / meterWindow)
+	if i < 0 || 2*i+1 >= len(l.peaks) {
+		return 0, 0
+	}
+	return l.peaks[2*i], l.peaks[2*i+1]
+}
+
+// runMeter plays the audio-only player while rendering a VU meter and the progress in the terminal,
+// until the player finishes or is interrupted. path is the file of the audio.
+func runMeter(player *webmplayer.Player, path string, exitOnEnd bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var levels meterLevels
+	go func() {
+		if err := levels.analyze(ctx, path); err != nil && ctx.Err() == nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}()
+
+	w := bufio.NewWriter(os.Stdout)
+	defer func() {
+		w.WriteString("\n")
+		w.Flush()
+	}()
+
+	ticker := time.NewTicker(meterWindow)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := player.Update(); err != nil {
+			return err
+		}
+
+		pos := player.Position()
+		l, r := levels.at(pos)
+		fmt.Fprintf(w, "\rL %s R %s %s / %s", meterBar(l), meterBar(r), formatDuration(pos), formatDuration(player.AudioDuration()))
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if exitOnEnd && player.IsFinished() {
+			return nil
+		}
+	}
+}
+
+// meterBar returns a bar for the peak level from -48 dB to 0 dB.
+func meterBar(peak float32) string {
+	const (
+		width = 24
+		floor = -48
+	)
+	n := 0
+	if peak > 0 {
+		db := 20 * math.Log10(float64(peak))
+		n = int(math.Round(float64(width) * (1 - min(db, 0)/floor)))
+		n = min(max(n, 0), width)
+	}
+	return "[" + strings.Repeat("#", n) + strings.Repeat(" ", width-n) + "]"
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d/time.Minute), int(d%time.Minute/time.Second))
+}
+
+// audioPath returns the first path with an audio track.
+func audioPath(paths []string) (string, error) {
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		info, err := webmplayer.Probe(f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+		if info.AudioCodecID != "" {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no audio track in %v", paths)
+}
@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer"
+)
+
+// ttySink is a webmplayer.FrameSink to render the frames in the terminal,
+// as half-block characters with 24-bit colors or as sixel graphics.
+//
+// The colors are converted with the JPEG's YCbCr matrix, which is good enough for a preview.
+type ttySink struct {
+	sixel bool
+
+	// columns and rows are the size of the terminal in characters.
+	columns int
+	rows    int
+
+	// width and height are the size of the downscaled frame in pixels.
+	width  int
+	height int
+
+	// pix is the downscaled latest frame in RGB.
+	pix   []byte
+	dirty bool
+
+	m sync.Mutex
+}
+
+// newTTYSink creates a ttySink for the terminal size in the COLUMNS and LINES environment variables, or 80x24.
+func newTTYSink(sixel bool) *ttySink {
+	envInt := func(name string, defaultValue int) int {
+		v, err := strconv.Atoi(os.Getenv(name))
+		if err != nil || v <= 0 {
+			return defaultValue
+		}
+		return v
+	}
+	return &ttySink{
+		sixel:   sixel,
+		columns: envInt("COLUMNS", 80),
+		rows:    envInt("LINES", 24),
+	}
+}
+
+// PushFrame implements webmplayer.FrameSink.
+func (t *ttySink) PushFrame(frame *image.YCbCr, pts time.Duration) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	b := frame.Bounds()
+	if t.width == 0 {
+		// A character cell is assumed to be twice as tall as wide: one cell has two pixels with half blocks,
+		// or 8x16 pixels with sixel. The last row is kept for the cursor.
+		maxW, maxH := t.columns, 2*(t.rows-1)
+		if t.sixel {
+			maxW, maxH = 8*t.columns, 16*(t.rows-1)
+		}
+		t.width, t.height = maxW, maxW*b.Dy()/b.Dx()
+		if t.height > maxH {
+			t.width, t.height = maxH*b.Dx()/b.Dy(), maxH
+		}
+		t.width = min(max(t.width, 1), b.Dx())
+		t.height = min(max(t.height, 1), b.Dy())
+		t.pix = make([]byte, 3*t.width*t.height)
+	}
+
+	for j := range t.height {
+		sy := b.Min.Y + j*b.Dy()/t.height
+		for i := range t.width {
+			sx := b.Min.X + i*b.Dx()/t.width
+			yi := frame.YOffset(sx, sy)
+			ci := frame.COffset(sx, sy)
+			r, g, b := color.YCbCrToRGB(frame.Y[yi], frame.Cb[ci], frame.Cr[ci])
+			k := 3 * (j*t.width + i)
+			t.pix[k], t.pix[k+1], t.pix[k+2] = r, g, b
+		}
+	}
+	t.dirty = true
+}
+
+// render writes the latest frame at the upper-left corner of the terminal if it is updated.
+func (t *ttySink) render(w *bufio.Writer) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if !t.dirty {
+		return nil
+	}
+	t.dirty = false
+
+	w.WriteString("\x1b[H")
+	if t.sixel {
+		t.writeSixel(w)
+	} else {
+		t.writeHalfBlocks(w)
+	}
+	return w.Flush()
+}
+
+// writeHalfBlocks writes the frame as upper half blocks, with the upper pixels as the foreground and the lower pixels as the background.
+func (t *ttySink) writeHalfBlocks(w *bufio.Writer) {
+	for j := 0; j < t.height; j += 2 {
+		for i := range t.width {
+			upper := t.pix[3*(j*t.width+i):]
+			fmt.Fprintf(w, "\x1b[38;2;%d;%d;%dm", upper[0], upper[1], upper[2])
+			if j+1 < t.height {
+				lower := t.pix[3*((j+1)*t.width+i):]
+				fmt.Fprintf(w, "\x1b[48;2;%d;%d;%dm", lower[0], lower[1], lower[2])
+			}
+			w.WriteString("▀")
+		}
+		w.WriteString("\x1b[0m\r\n")
+	}
+}
+
+// sixelLevels is the number of levels of each color component in the sixel palette, which is a color cube.
+const sixelLevels = 6
+
+// writeSixel writes the frame as sixel graphics with a fixed palette.
+//
+// https://vt100.net/docs/vt3xx-gp/chapter14.html
+func (t *ttySink) writeSixel(w *bufio.Writer) {
+	fmt.Fprintf(w, "\x1bPq\"1;1;%d;%d", t.width, t.height)
+	for r := range sixelLevels {
+		for g := range sixelLevels {
+			for b := range sixelLevels {
+				const s = sixelLevels - 1
+				fmt.Fprintf(w, "#%d;2;%d;%d;%d", (r*sixelLevels+g)*sixelLevels+b, r*100/s, g*100/s, b*100/s)
+			}
+		}
+	}
+
+	index := func(k int) int {
+		q := func(v byte) int {
+			return (int(v)*(sixelLevels-1) + 127) / 255
+		}
+		return (q(t.pix[k])*sixelLevels+q(t.pix[k+1]))*sixelLevels + q(t.pix[k+2])
+	}
+
+	const colors = sixelLevels * sixelLevels * sixelLevels
+	var used [colors]bool
+	bits := make([]byte, colors*t.width)
+	for band := 0; band < t.height; band += 6 {
+		// Collect the six vertical pixels of each column for each color.
+		clear(bits)
+		clear(used[:])
+		for dy := 0; dy < 6 && band+dy < t.height; dy++ {
+			for i := range t.width {
+				c := index(3 * ((band+dy)*t.width + i))
+				bits[c*t.width+i] |= 1 << dy
+				used[c] = true
+			}
+		}
+		for c := range colors {
+			if !used[c] {
+				continue
+			}
+			fmt.Fprintf(w, "#%d", c)
+			row := bits[c*t.width : (c+1)*t.width]
+			for i := 0; i < len(row); {
+				n := 1
+				for i+n < len(row) && row[i+n] == row[i] {
+					n++
+				}
+				ch := byte('?' + row[i])
+				if n > 3 {
+					fmt.Fprintf(w, "!%d%c", n, ch)
+				} else {
+					for range n {
+						w.WriteByte(ch)
+					}
+				}
+				i += n
+			}
+			// Return to the start of the band for the next color.
+			w.WriteByte('$')
+		}
+		w.WriteByte('-')
+	}
+	w.WriteString("\x1b\\")
+}
+
+// runTTY plays the player while rendering the frames of sink in the terminal, until the player finishes or is interrupted.
+func runTTY(player *webmplayer.Player, sink *ttySink, exitOnEnd bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	w := bufio.NewWriter(os.Stdout)
+	// Clear the screen and hide the cursor.
+	w.WriteString("\x1b[2J\x1b[?25l")
+	defer func() {
+		w.WriteString("\x1b[0m\x1b[?25h\r\n")
+		w.Flush()
+	}()
+
+	ticker := time.NewTicker(time.Second / 30)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := player.Update(); err != nil {
+			return err
+		}
+		if err := sink.render(w); err != nil {
+			return err
+		}
+		if exitOnEnd && player.IsFinished() {
+			return nil
+		}
+	}
+}
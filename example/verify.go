@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer"
+)
+
+// verify decodes the given files as fast as possible, and reports decode errors and drifts.
+func verify(paths []string) error {
+	var failed int
+	for _, path := range paths {
+		n, err := verifyFile(path)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("verification failed for %d file(s)", failed)
+	}
+	return nil
+}
+
+// verifyFile verifies the given file, and returns the number of decode errors.
+func verifyFile(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var (
+		videoFrames    int
+		videoEnd       time.Duration
+		audioSamples   int
+		audioStart     time.Duration = -1
+		audioEnd       time.Duration
+		maxAudioDrift  time.Duration
+		decodeErrCount int
+	)
+
+	start := time.Now()
+	if err := webmplayer.Decode(f, &webmplayer.DecodeOptions{
+		OnVideoFrame: func(frame image.Image, pts time.Duration) error {
+			videoFrames++
+			videoEnd = max(videoEnd, pts)
+			return nil
+		},
+		OnAudioSamples: func(samples []float32, samplingFrequency int, pts time.Duration) error {
+			if audioStart < 0 {
+				audioStart = pts
+			}
+			// Compare the timestamp with the one computed from the number of decoded samples.
+			expected := audioStart + time.Duration(audioSamples)*time.Second/time.Duration(samplingFrequency)
+			drift := pts - expected
+			if drift < 0 {
+				drift = -drift
+			}
+			maxAudioDrift = max(maxAudioDrift, drift)
+
+			n := len(samples) / 2
+			audioSamples += n
+			audioEnd = pts + time.Duration(n)*time.Second/time.Duration(samplingFrequency)
+			return nil
+		},
+		OnError: func(err error) error {
+			decodeErrCount++
+			slog.Error("Decode error", "file", path, "error", err)
+			return nil
+		},
+	}); err != nil {
+		return 0, err
+	}
+
+	attrs := []any{
+		"file", path,
+		"elapsed", time.Since(start),
+		"errors", decodeErrCount,
+	}
+	if videoFrames > 0 {
+		attrs = append(attrs, "videoFrames", videoFrames, "videoEnd", videoEnd)
+	}
+	if audioSamples > 0 {
+		attrs = append(attrs, "audioSamples", audioSamples, "audioEnd", audioEnd, "maxAudioDrift", maxAudioDrift)
+	}
+	if videoFrames > 0 && audioSamples > 0 {
+		attrs = append(attrs, "trackDrift", videoEnd-audioEnd)
+	}
+	slog.Info("Verified", attrs...)
+
+	return decodeErrCount, nil
+}
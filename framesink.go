@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"image"
+	"time"
+)
+
+// FrameSink presents the video frames instead of Ebitengine, e.g. with SDL, in a terminal with sixel graphics, or in a headless pipeline.
+//
+// The player's clock, frame dropping and audio work as usual with a FrameSink.
+type FrameSink interface {
+	// PushFrame is called in the decoding goroutine with each frame to show at its presentation time.
+	// PushFrame should return quickly not to delay the video.
+	//
+	// frame is without the cropped edges. frame must not be modified, and is valid only during the call.
+	// The alpha channel is not included even if the video has it.
+	PushFrame(frame *image.YCbCr, pts time.Duration)
+}
@@ -1,11 +1,12 @@
 module github.com/hajimehoshi/webmplayer
 
-go 1.22.0
+go 1.23.0
 
 require (
-	github.com/ebml-go/webm v0.0.0-20221117133942-84fa5245cf70
+	github.com/ebml-go/ebml v0.0.0-20160925193348-ca8851a10894
 	github.com/hajimehoshi/ebiten/v2 v2.8.5
-	github.com/xlab/libvpx-go v0.0.0-20220203233824-652b2616315c
+	github.com/hajimehoshi/go-mp3 v0.3.4
+	github.com/petar/GoLLRB v0.0.0-20130427215148-53be0d36a84c
 )
 
 require (
@@ -13,9 +14,7 @@ require (
 	github.com/ebitengine/hideconsole v1.0.0 // indirect
 	github.com/ebitengine/oto/v3 v3.3.1 // indirect
 	github.com/ebitengine/purego v0.8.0 // indirect
-	github.com/ebml-go/ebml v0.0.0-20160925193348-ca8851a10894 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
-	github.com/petar/GoLLRB v0.0.0-20130427215148-53be0d36a84c // indirect
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.25.0 // indirect
 )
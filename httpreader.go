@@ -0,0 +1,478 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultReadAheadSize = 1 << 20
+	defaultMaxRetries    = 3
+
+	// httpRetryBaseDelay and httpRetryMaxDelay are the delays of the exponential backoff of the retries.
+	httpRetryBaseDelay = 250 * time.Millisecond
+	httpRetryMaxDelay  = 4 * time.Second
+
+	// httpChunkSize is the size to read a response body at a time.
+	httpChunkSize = 32 * 1024
+
+	// httpHistorySize is the size of the data kept after reading, to seek backward a little without a new request.
+	// The demuxer seeks back to the headers of elements it has just read.
+	httpHistorySize = 64 * 1024
+)
+
+// URLOptions represents options for NewPlayerFromURL.
+type URLOptions struct {
+	// Player is the options of the player.
+	//
+	// The default (zero) value is nil, which uses the default values.
+	Player *PlayerOptions
+
+	// Client is the HTTP client to send the requests.
+	//
+	// The default (zero) value is nil, which uses http.DefaultClient.
+	Client *http.Client
+
+	// Header is the additional header of the requests, e.g. for authorization.
+	//
+	// The default (zero) value is nil.
+	Header http.Header
+
+	// ReadAheadSize is the maximum size in bytes of the data read ahead of the player in the background.
+	// A larger size tolerates a slower or more unstable network at the cost of memory.
+	//
+	// The default (zero) value is 1 MiB.
+	ReadAheadSize int
+
+	// MaxRetries is the maximum number of retries of a failed request, with an exponential backoff.
+	// A request is retried for a network error or a server error (5xx or 429), but not for a client error like 404.
+	// A negative value disables retries.
+	//
+	// The default (zero) value is 3.
+	MaxRetries int
+}
+
+// NewPlayerFromURL creates a new player to play the stream at the given HTTP or HTTPS URL.
+//
+// The stream is read by Range requests, so that the player can seek without downloading the whole stream.
+// If the server doesn't support Range requests, the stream is downloaded from the start again for each seek.
+//
+// ctx is used for all the requests. When ctx is canceled, the requests are aborted and the player stops reading the stream.
+// The requests are also aborted when the player is closed.
+//
+// If options is nil, the default values are used.
+func NewPlayerFromURL(ctx context.Context, url string, options *URLOptions) (*Player, error) {
+	if options == nil {
+		options = &URLOptions{}
+	}
+	r, err := newHTTPReader(ctx, url, options)
+	if err != nil {
+		return nil, err
+	}
+	p, err := New(options.Player, r)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	p.httpReader = r
+	return p, nil
+}
+
+// httpStatusError is an error for an unexpected HTTP status.
+type httpStatusError struct {
+	code int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("webmplayer: unexpected HTTP status: %d %s", e.code, http.StatusText(e.code))
+}
+
+// httpReader is an io.ReadSeeker of a resource by HTTP Range requests.
+//
+// httpReader is not goroutine-safe except for Close.
+type httpReader struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	client     *http.Client
+	url        string
+	header     http.Header
+	readAhead  int
+	maxRetries int
+
+	// size is the size of the resource, or -1 if unknown.
+	size int64
+
+	// pos is the position to read next.
+	pos int64
+
+	// body is the current response body, which is read at bodyPos.
+	body    *prefetcher
+	bodyPos int64
+
+	// history is the data read from body just before bodyPos.
+	history []byte
+}
+
+func newHTTPReader(ctx context.Context, rawURL string, options *URLOptions) (*httpReader, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	h := &httpReader{
+		ctx:        ctx,
+		cancel:     cancel,
+		client:     options.Client,
+		url:        rawURL,
+		header:     options.Header,
+		readAhead:  options.ReadAheadSize,
+		maxRetries: options.MaxRetries,
+		size:       -1,
+	}
+	if h.client == nil {
+		h.client = http.DefaultClient
+	}
+	if h.readAhead <= 0 {
+		h.readAhead = defaultReadAheadSize
+	}
+	if h.maxRetries == 0 {
+		h.maxRetries = defaultMaxRetries
+	}
+
+	// Send the first request to check the resource and to know its size.
+	if err := h.retry(func() error {
+		return h.open()
+	}); err != nil {
+		cancel()
+		return nil, err
+	}
+	return h, nil
+}
+
+// Name returns the path of the URL.
+func (h *httpReader) Name() string {
+	u, err := url.Parse(h.url)
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}
+
+func (h *httpReader) Read(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	if h.size >= 0 && h.pos >= h.size {
+		return 0, io.EOF
+	}
+
+	if h.body != nil && h.pos < h.bodyPos && h.bodyPos-h.pos <= int64(len(h.history)) {
+		n := copy(buf, h.history[int64(len(h.history))-(h.bodyPos-h.pos):])
+		h.pos += int64(n)
+		return n, nil
+	}
+
+	var n int
+	err := h.retry(func() error {
+		if err := h.prepareBody(); err != nil {
+			return err
+		}
+		var err error
+		n, err = h.body.Read(buf)
+		h.pos += int64(n)
+		h.bodyPos += int64(n)
+		h.appendHistory(buf[:n])
+		if n > 0 {
+			// A following error is returned at the next Read.
+			return nil
+		}
+		if err == io.EOF {
+			if h.size < 0 {
+				return io.EOF
+			}
+			// The connection is closed before the end.
+			err = io.ErrUnexpectedEOF
+		}
+		h.closeBody()
+		return err
+	})
+	return n, err
+}
+
+func (h *httpReader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = h.pos + offset
+	case io.SeekEnd:
+		if h.size < 0 {
+			return 0, fmt.Errorf("webmplayer: seeking from the end of a resource of an unknown size")
+		}
+		pos = h.size + offset
+	default:
+		return 0, fmt.Errorf("webmplayer: invalid whence: %d", whence)
+	}
+	if pos < 0 {
+		return 0, fmt.Errorf("webmplayer: negative position: %d", pos)
+	}
+	h.pos = pos
+	return pos, nil
+}
+
+// Close aborts the requests. Close can be called from any goroutine.
+func (h *httpReader) Close() error {
+	h.cancel()
+	return nil
+}
+
+// prepareBody makes h.body ready to read at h.pos.
+func (h *httpReader) prepareBody() error {
+	if h.body != nil && h.pos != h.bodyPos {
+		if h.pos > h.bodyPos && h.pos-h.bodyPos <= int64(h.readAhead) {
+			// Skip the data in the current response instead of sending a new request, as the data is likely read ahead.
+			n, err := io.CopyN(io.Discard, h.body, h.pos-h.bodyPos)
+			h.bodyPos += n
+			h.history = h.history[:0]
+			if err != nil {
+				h.closeBody()
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return err
+			}
+		} else {
+			h.closeBody()
+		}
+	}
+	if h.body == nil {
+		return h.open()
+	}
+	return nil
+}
+
+// open sends a request to read the resource from h.pos.
+func (h *httpReader) open() error {
+	req, err := http.NewRequestWithContext(h.ctx, http.MethodGet, h.url, nil)
+	if err != nil {
+		return err
+	}
+	if h.header != nil {
+		req.Header = h.header.Clone()
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", h.pos))
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	var skip int64
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		start, size, err := parseContentRange(res.Header.Get("Content-Range"))
+		if err != nil {
+			res.Body.Close()
+			return err
+		}
+		if start != h.pos {
+			res.Body.Close()
+			return fmt.Errorf("webmplayer: the response starts at %d instead of %d", start, h.pos)
+		}
+		if size >= 0 {
+			h.size = size
+		}
+	case http.StatusOK:
+		// The server ignores the range and sends the whole resource.
+		if res.ContentLength >= 0 {
+			h.size = res.ContentLength
+		}
+		skip = h.pos
+	case http.StatusRequestedRangeNotSatisfiable:
+		res.Body.Close()
+		if h.size >= 0 && h.pos >= h.size {
+			return io.EOF
+		}
+		return &httpStatusError{code: res.StatusCode}
+	default:
+		res.Body.Close()
+		return &httpStatusError{code: res.StatusCode}
+	}
+
+	h.body = newPrefetcher(res.Body, h.readAhead)
+	h.bodyPos = h.pos - skip
+	if skip > 0 {
+		if _, err := io.CopyN(io.Discard, h.body, skip); err != nil {
+			h.closeBody()
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		h.bodyPos = h.pos
+	}
+	return nil
+}
+
+func (h *httpReader) closeBody() {
+	if h.body == nil {
+		return
+	}
+	h.body.Close()
+	h.body = nil
+	h.history = h.history[:0]
+}
+
+// appendHistory appends the data read from body to the history, and drops the old data.
+func (h *httpReader) appendHistory(data []byte) {
+	h.history = append(h.history, data...)
+	if len(h.history) > 2*httpHistorySize {
+		h.history = h.history[:copy(h.history, h.history[len(h.history)-httpHistorySize:])]
+	}
+}
+
+// retry calls f until f succeeds, f fails with a permanent error, or the retries run out.
+func (h *httpReader) retry(f func() error) error {
+	for i := 0; ; i++ {
+		err := f()
+		if err == nil || err == io.EOF || i >= h.maxRetries || !isRetryableHTTPError(err) || h.ctx.Err() != nil {
+			return err
+		}
+		delay := min(httpRetryBaseDelay<<i, httpRetryMaxDelay)
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-h.ctx.Done():
+			t.Stop()
+			return h.ctx.Err()
+		}
+	}
+}
+
+func isRetryableHTTPError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var s *httpStatusError
+	if errors.As(err, &s) {
+		return s.code >= 500 || s.code == http.StatusTooManyRequests
+	}
+	// A network error.
+	return true
+}
+
+// parseContentRange parses a Content-Range header like "bytes 100-199/1000", and returns the first position and the size.
+// The size is -1 if it is unknown ("*").
+func parseContentRange(value string) (start, size int64, err error) {
+	rest, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, 0, fmt.Errorf("webmplayer: invalid Content-Range: %q", value)
+	}
+	rng, total, ok := strings.Cut(rest, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("webmplayer: invalid Content-Range: %q", value)
+	}
+	first, _, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("webmplayer: invalid Content-Range: %q", value)
+	}
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("webmplayer: invalid Content-Range: %q", value)
+	}
+	if total == "*" {
+		return start, -1, nil
+	}
+	size, err = strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("webmplayer: invalid Content-Range: %q", value)
+	}
+	return start, size, nil
+}
+
+// prefetcher reads a response body ahead in the background, up to a limit.
+type prefetcher struct {
+	body  io.ReadCloser
+	limit int
+
+	buf    bytes.Buffer
+	err    error
+	closed bool
+	m      sync.Mutex
+	cond   *sync.Cond
+}
+
+func newPrefetcher(body io.ReadCloser, limit int) *prefetcher {
+	p := &prefetcher{
+		body:  body,
+		limit: limit,
+	}
+	p.cond = sync.NewCond(&p.m)
+	go p.loop()
+	return p
+}
+
+func (p *prefetcher) loop() {
+	chunk := make([]byte, httpChunkSize)
+	for {
+		p.m.Lock()
+		for !p.closed && p.buf.Len() >= p.limit {
+			p.cond.Wait()
+		}
+		if p.closed {
+			p.m.Unlock()
+			return
+		}
+		p.m.Unlock()
+
+		n, err := p.body.Read(chunk)
+
+		p.m.Lock()
+		p.buf.Write(chunk[:n])
+		if err != nil {
+			p.err = err
+		}
+		p.cond.Broadcast()
+		p.m.Unlock()
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (p *prefetcher) Read(buf []byte) (int, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for p.buf.Len() == 0 && p.err == nil && !p.closed {
+		p.cond.Wait()
+	}
+	if p.buf.Len() == 0 {
+		if p.err == nil {
+			return 0, io.ErrClosedPipe
+		}
+		return 0, p.err
+	}
+	n, _ := p.buf.Read(buf)
+	p.cond.Broadcast()
+	return n, nil
+}
+
+// Close stops reading ahead and closes the body.
+func (p *prefetcher) Close() {
+	p.m.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.m.Unlock()
+	p.body.Close()
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+// Package allocstats counts the allocations of each subsystem, when built with the webmplayer_allocstats build tag.
+//
+// Only the allocations of buffers that scale with the media, like packets and frames, are counted.
+// Without the build tag, Add does nothing and Enabled is false, so the counting costs nothing.
+package allocstats
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Subsystem represents a part of the player that allocates buffers.
+type Subsystem int
+
+const (
+	Demux Subsystem = iota
+	AudioDecode
+	VideoDecode
+	Upload
+
+	subsystemCount
+)
+
+func (s Subsystem) String() string {
+	switch s {
+	case Demux:
+		return "demux"
+	case AudioDecode:
+		return "audio decode"
+	case VideoDecode:
+		return "video decode"
+	case Upload:
+		return "upload"
+	default:
+		return fmt.Sprintf("Subsystem(%d)", int(s))
+	}
+}
+
+// Report returns the counts and the sizes of the allocations of all the subsystems since the process started.
+func Report() string {
+	var b strings.Builder
+	b.WriteString("webmplayer: allocations:\n")
+	for s := range subsystemCount {
+		count, bytes := Get(s)
+		fmt.Fprintf(&b, "  %-12s %8d allocs %12d bytes\n", s.String()+":", count, bytes)
+	}
+	return b.String()
+}
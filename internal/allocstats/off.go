@@ -0,0 +1,18 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+//go:build !webmplayer_allocstats
+
+package allocstats
+
+// Enabled reports whether the allocations are counted.
+const Enabled = false
+
+// Add counts an allocation of the given size in bytes.
+func Add(s Subsystem, bytes int) {
+}
+
+// Get returns the count and the total size in bytes of the allocations of the subsystem.
+func Get(s Subsystem) (count, bytes int64) {
+	return 0, 0
+}
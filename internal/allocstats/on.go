@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+//go:build webmplayer_allocstats
+
+package allocstats
+
+import (
+	"sync/atomic"
+)
+
+// Enabled reports whether the allocations are counted.
+const Enabled = true
+
+var (
+	counts [subsystemCount]atomic.Int64
+	sizes  [subsystemCount]atomic.Int64
+)
+
+// Add counts an allocation of the given size in bytes.
+func Add(s Subsystem, bytes int) {
+	counts[s].Add(1)
+	sizes[s].Add(int64(bytes))
+}
+
+// Get returns the count and the total size in bytes of the allocations of the subsystem.
+func Get(s Subsystem) (count, bytes int64) {
+	return counts[s].Load(), sizes[s].Load()
+}
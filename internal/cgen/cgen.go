@@ -247,6 +247,13 @@ entries:
 				}
 
 				p := m[2]
+				// A path starting with "./" is relative to the directory of the including file.
+				if strings.HasPrefix(p, "./") {
+					p = path.Join(path.Dir(entry.name), p)
+					for _, dir := range entry.context.options.TopDirs {
+						p = strings.TrimPrefix(p, dir+"/")
+					}
+				}
 				for strings.HasPrefix(p, "../") {
 					p = strings.TrimPrefix(p, "../")
 				}
@@ -0,0 +1,614 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+// Package codec implements the video and audio decoders, which don't depend on Ebitengine.
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/go-mp3"
+
+	"github.com/hajimehoshi/webmplayer/internal/allocstats"
+	"github.com/hajimehoshi/webmplayer/internal/flac"
+	"github.com/hajimehoshi/webmplayer/internal/libopus"
+	"github.com/hajimehoshi/webmplayer/internal/libvorbis"
+	"github.com/hajimehoshi/webmplayer/internal/webm"
+)
+
+type AudioCodec string
+
+const (
+	AudioCodecVorbis    AudioCodec = "A_VORBIS"
+	AudioCodecOpus      AudioCodec = "A_OPUS"
+	AudioCodecPCMIntLit AudioCodec = "A_PCM/INT/LIT"
+	AudioCodecPCMIntBig AudioCodec = "A_PCM/INT/BIG"
+	AudioCodecPCMFloat  AudioCodec = "A_PCM/FLOAT/IEEE"
+	AudioCodecFLAC      AudioCodec = "A_FLAC"
+	AudioCodecMP3       AudioCodec = "A_MPEG/L3"
+)
+
+const (
+	// audioGapThreshold is the minimum gap between the packets of the codecs other than Opus to insert silence.
+	// A shorter gap can be from the rounding of the timecodes.
+	audioGapThreshold = 20 * time.Millisecond
+
+	// maxAudioGap is the maximum gap between packets to fill. A longer gap is treated as broken timecodes and is ignored.
+	maxAudioGap = 10 * time.Second
+
+	// opusLossThreshold is the minimum gap between Opus packets to be treated as lost packets.
+	// A gap less than the shortest Opus frame, 2.5 ms, can be from the rounding of the timecodes.
+	opusLossThreshold = 3 * time.Millisecond
+
+	// opusMaxConcealment is the maximum duration of lost Opus packets to conceal.
+	// The concealment fades out and is no longer useful for longer losses.
+	opusMaxConcealment = 120 * time.Millisecond
+
+	// opusMaxPacketDuration is the maximum duration of an Opus packet, which is the duration of the buffer to decode a packet.
+	// A packet can have multiple frames up to 120 ms, e.g. 5760 samples at 48 kHz.
+	opusMaxPacketDuration = 120 * time.Millisecond
+)
+
+// AudioDecoder decodes the packets of an audio track into interleaved stereo samples.
+type AudioDecoder struct {
+	codec             AudioCodec
+	channels          int
+	samplingFrequency int
+
+	// voInfo must be kept as voDPS has a reference to it.
+	voInfo  *libvorbis.Info
+	voDSP   *libvorbis.DspState
+	voBlock *libvorbis.Block
+
+	opDecoder *libopus.MSDecoder
+	opPCM     []float32
+	// opGain is the linear output gain in the OpusHead.
+	opGain float32
+	// opPreSkip is the number of samples to discard at the start of the stream, and opSkip is the remaining number.
+	opPreSkip int
+	opSkip    int
+	// opLastSamples is the number of samples per channel in the last decoded packet.
+	opLastSamples int
+
+	pcmFormat pcmFormat
+	pcmBuf    []float32
+
+	flacDecoder *flac.Decoder
+	flacPCM     [][]float32
+
+	mp3Decoder *mp3.Decoder
+	mp3Source  mp3Source
+	mp3Buf     []byte
+	// mp3Skip indicates whether the next decoded frame is discarded.
+	mp3Skip bool
+
+	// downmix mixes the channels down to stereo if the track has more than two channels.
+	downmix *downmixer
+
+	// timecode is the timecode of the next decoded sample.
+	timecode time.Duration
+
+	// continuous indicates whether the next packet follows the last decoded packet without a reset.
+	continuous bool
+}
+
+// NewAudioDecoder creates an audio decoder for the track.
+// warn is called with a recoverable problem in the track if not nil.
+func NewAudioDecoder(track *webm.TrackEntry, warn func(err error)) (*AudioDecoder, error) {
+	if warn == nil {
+		warn = func(err error) {}
+	}
+	codec := AudioCodec(track.CodecID)
+	codecPrivate := track.CodecPrivate
+	// codecDelay is the duration of the samples to discard at the start of the stream, which is used when the codec's header doesn't have it.
+	codecDelay := time.Duration(track.CodecDelay)
+	channels := int(track.Audio.Channels)
+	samplingFrequency := int(track.Audio.SamplingFrequency)
+	d := &AudioDecoder{
+		channels:          channels,
+		samplingFrequency: samplingFrequency,
+		codec:             codec,
+	}
+	switch codec {
+	case AudioCodecVorbis:
+		info, comment, err := readVorbisCodecPrivate(codecPrivate)
+		if err != nil {
+			return nil, err
+		}
+		libvorbis.CommentClear(comment)
+		d.voInfo = info
+
+		// The Vorbis headers are what the decoder actually uses, so trust them over the container like other players.
+		if info.Channels() != channels {
+			warn(fmt.Errorf("webmplayer: the channel count in the container (%d) doesn't match the Vorbis headers (%d); the Vorbis headers are used", channels, info.Channels()))
+			d.channels = info.Channels()
+		}
+		if info.Rate() != samplingFrequency {
+			warn(fmt.Errorf("webmplayer: the sampling frequency in the container (%d) doesn't match the Vorbis headers (%d); the Vorbis headers are used", samplingFrequency, info.Rate()))
+			d.samplingFrequency = info.Rate()
+		}
+		if d.channels > 2 {
+			m, err := newDownmixer(d.channels, vorbisChannelLayouts[:])
+			if err != nil {
+				return nil, err
+			}
+			d.downmix = m
+		}
+
+		dsp, err := libvorbis.SynthesisInit(info)
+		if err != nil {
+			return nil, fmt.Errorf("webmplayer: libvorbis.SynthesisInit failed: %w", err)
+		}
+		d.voDSP = dsp
+
+		block, err := libvorbis.BlockInit(d.voDSP)
+		if err != nil {
+			return nil, fmt.Errorf("webmplayer: libvorbis.BlockInit failed: %w", err)
+		}
+		d.voBlock = block
+
+		return d, nil
+
+	case AudioCodecOpus:
+		// Some muxers omit the OpusHead in CodecPrivate, or the audio settings.
+		// A mono or stereo stream doesn't need the OpusHead, so play with the track's settings or the defaults instead of failing.
+		var head *opusHead
+		if len(codecPrivate) == 0 {
+			warn(fmt.Errorf("webmplayer: the Opus track has no CodecPrivate; the track's channels and sampling frequency are used"))
+		} else if h, err := parseOpusHead(codecPrivate); err != nil {
+			warn(fmt.Errorf("%w; the track's channels are used", err))
+		} else {
+			// The OpusHead is what the decoder actually uses, so trust it over the container.
+			if channels != 0 && h.channels != channels {
+				warn(fmt.Errorf("webmplayer: the channel count in the container (%d) doesn't match the OpusHead (%d); the OpusHead is used", channels, h.channels))
+			}
+			head = h
+			channels = h.channels
+		}
+		if channels == 0 {
+			warn(fmt.Errorf("webmplayer: the Opus track has no channel count; stereo is assumed"))
+			channels = 2
+		}
+		if head == nil {
+			if channels > 2 {
+				return nil, fmt.Errorf("webmplayer: the Opus track with %d channels has no valid OpusHead", channels)
+			}
+			head = defaultOpusHead(channels)
+			head.preSkip = int(int64(codecDelay) * 48000 / int64(time.Second))
+		}
+		if channels > 2 {
+			if head.mappingFamily != 1 {
+				return nil, fmt.Errorf("webmplayer: unsupported Opus channel mapping family: %d", head.mappingFamily)
+			}
+			m, err := newDownmixer(channels, vorbisChannelLayouts[:])
+			if err != nil {
+				return nil, err
+			}
+			d.downmix = m
+		}
+		switch samplingFrequency {
+		case 8000, 12000, 16000, 24000, 48000:
+		default:
+			// Opus can be decoded at 48 kHz regardless of the original sampling frequency.
+			warn(fmt.Errorf("webmplayer: the Opus track has an unsupported sampling frequency %d; 48000 is used", samplingFrequency))
+			samplingFrequency = 48000
+		}
+		d.channels = channels
+		d.samplingFrequency = samplingFrequency
+
+		var err error
+		d.opDecoder, err = libopus.MSDecoderCreate(samplingFrequency, channels, head.streams, head.coupledStreams, head.mapping)
+		if err != nil {
+			return nil, fmt.Errorf("webmplayer: libopus.MSDecoderCreate failed: %w", err)
+		}
+		d.opPCM = make([]float32, int(int64(samplingFrequency)*int64(opusMaxPacketDuration)/int64(time.Second))*channels)
+		d.opGain = float32(math.Pow(10, head.outputGain/20))
+		// The pre-skip is in samples at 48 kHz.
+		d.opPreSkip = head.preSkip * samplingFrequency / 48000
+		d.opSkip = d.opPreSkip
+		return d, nil
+
+	case AudioCodecPCMIntLit, AudioCodecPCMIntBig, AudioCodecPCMFloat:
+		f, err := newPCMFormat(codec, int(track.BitDepth))
+		if err != nil {
+			return nil, err
+		}
+		d.pcmFormat = f
+		if channels == 0 {
+			return nil, fmt.Errorf("webmplayer: the PCM track has no channel count")
+		}
+		if channels > 2 {
+			m, err := newDownmixer(channels, waveChannelLayouts[:])
+			if err != nil {
+				return nil, err
+			}
+			d.downmix = m
+		}
+		return d, nil
+
+	case AudioCodecFLAC:
+		info, err := flac.ParseStreamInfo(codecPrivate)
+		if err != nil {
+			return nil, err
+		}
+		// The STREAMINFO is what the frames are encoded with, so trust it over the container.
+		if info.Channels != channels {
+			warn(fmt.Errorf("webmplayer: the channel count in the container (%d) doesn't match the FLAC STREAMINFO (%d); the STREAMINFO is used", channels, info.Channels))
+			d.channels = info.Channels
+		}
+		if info.SampleRate != samplingFrequency {
+			warn(fmt.Errorf("webmplayer: the sampling frequency in the container (%d) doesn't match the FLAC STREAMINFO (%d); the STREAMINFO is used", samplingFrequency, info.SampleRate))
+			d.samplingFrequency = info.SampleRate
+		}
+		if d.channels > 2 {
+			m, err := newDownmixer(d.channels, waveChannelLayouts[:])
+			if err != nil {
+				return nil, err
+			}
+			d.downmix = m
+		}
+		d.flacDecoder = flac.NewDecoder(info)
+		return d, nil
+
+	case AudioCodecMP3:
+		// go-mp3 always decodes MP3 into stereo samples.
+		if channels != 1 && channels != 2 {
+			return nil, fmt.Errorf("webmplayer: unsupported channel count for MP3: %d", channels)
+		}
+		return d, nil
+
+	default:
+		return nil, fmt.Errorf("webmplayer: unsupported audio codec: %s", codec)
+	}
+}
+
+// Channels returns the number of the channels of the track, which might be more than the two channels of the decoded samples.
+func (d *AudioDecoder) Channels() int {
+	return d.channels
+}
+
+// SamplingFrequency returns the sampling frequency of the decoded samples.
+func (d *AudioDecoder) SamplingFrequency() int {
+	return d.samplingFrequency
+}
+
+// Decode decodes the packet data and appends the decoded stereo samples to dst.
+// timecode is the packet's timecode, or webm.BadTC if the packet doesn't have its own timecode, e.g. a laced packet.
+// discardPadding is the duration of the samples to discard at the end of the packet.
+//
+// Decode returns the appended samples and the timecode of the first decoded sample.
+// If an error occurs, the samples decoded until the error are still appended.
+func (d *AudioDecoder) Decode(dst []float32, data []byte, timecode, discardPadding time.Duration) ([]float32, time.Duration, error) {
+	origLen := len(dst)
+
+	tc := timecode
+	if tc == webm.BadTC {
+		// A laced packet doesn't have its own timecode.
+		tc = d.timecode
+	}
+
+	if gap := tc - d.timecode; timecode != webm.BadTC && d.continuous && gap <= maxAudioGap {
+		frames := func(duration time.Duration) int {
+			return int(int64(duration) * int64(d.samplingFrequency) / int64(time.Second))
+		}
+		switch {
+		case d.codec == AudioCodecOpus && gap >= opusLossThreshold:
+			// The packets before this packet are lost, e.g. in network playback.
+			// Conceal them with the forward error correction data in this packet, or by the packet loss concealment.
+			// The rest of a long gap is silent, as the concealment fades out.
+			conceal := min(gap, opusMaxConcealment)
+			var next []byte
+			if conceal == gap {
+				next = data
+			}
+			dst = d.concealOpus(dst, frames(conceal), next)
+			dst = appendSilence(dst, frames(gap)-(len(dst)-origLen)/2)
+			// The filled samples start at the end of the previous packet.
+			tc = d.timecode
+		case d.codec != AudioCodecOpus && gap >= audioGapThreshold:
+			// The timecodes are discontinuous, e.g. in an edited file.
+			// Insert silence so that the samples don't drift from the timecodes, and then from the video.
+			dst = appendSilence(dst, frames(gap))
+			tc = d.timecode
+		}
+	}
+
+	switch d.codec {
+	case AudioCodecVorbis:
+		packet := &libvorbis.OggPacket{
+			Packet: data,
+		}
+		if err := libvorbis.Synthesis(d.voBlock, packet); err != nil {
+			return dst, 0, fmt.Errorf("webmplayer: libvorbis.Synthesis failed: %w", err)
+		}
+
+		if err := libvorbis.SynthesisBlockin(d.voDSP, d.voBlock); err != nil {
+			return dst, 0, fmt.Errorf("webmplayer: libvorbis.SynthesisBlockin failed: %w", err)
+		}
+
+		for pcm := libvorbis.SynthesisPcmout(d.voDSP); len(pcm) > 0 && len(pcm[0]) > 0; pcm = libvorbis.SynthesisPcmout(d.voDSP) {
+			switch d.channels {
+			case 1:
+				for i := range pcm[0] {
+					v := pcm[0][i]
+					dst = append(dst, v, v)
+				}
+			case 2:
+				for i := range pcm[0] {
+					for ch := range pcm {
+						v := pcm[ch][i]
+						dst = append(dst, v)
+					}
+				}
+			default:
+				dst = d.downmix.appendPlanar(dst, pcm)
+			}
+			if err := libvorbis.SynthesisRead(d.voDSP, len(pcm[0])); err != nil {
+				return dst, 0, fmt.Errorf("webmplayer: libvorbis.SynthesisRead failed: %w", err)
+			}
+		}
+
+	case AudioCodecOpus:
+		sampleCount := d.opDecoder.DecodeFloat(data, d.opPCM, 0)
+		if sampleCount < 0 {
+			// The packet is damaged. Conceal it by the packet loss concealment for the packet's duration.
+			n := OpusPacketSamples(data) * d.samplingFrequency / 48000
+			if n == 0 {
+				n = d.opLastSamples
+			}
+			dst = d.concealOpus(dst, n, nil)
+			break
+		}
+		d.opLastSamples = sampleCount
+		if d.opSkip > 0 {
+			// Discard the decoder's delay at the start of the stream. The first remaining sample is at the packet's timecode.
+			n := min(d.opSkip, sampleCount)
+			d.opSkip -= n
+			copy(d.opPCM, d.opPCM[n*d.channels:sampleCount*d.channels])
+			sampleCount -= n
+		}
+		if discardPadding > 0 {
+			// Discard the padding at the end of the stream.
+			n := int((int64(discardPadding)*int64(d.samplingFrequency) + int64(time.Second)/2) / int64(time.Second))
+			sampleCount -= min(n, sampleCount)
+		}
+		dst = d.appendOpusPCM(dst, sampleCount)
+
+	case AudioCodecPCMIntLit, AudioCodecPCMIntBig, AudioCodecPCMFloat:
+		dst = d.appendPCM(dst, data)
+
+	case AudioCodecFLAC:
+		var err error
+		dst, err = d.appendFLAC(dst, data)
+		if err != nil {
+			return dst, 0, err
+		}
+
+	case AudioCodecMP3:
+		var err error
+		dst, tc, err = d.appendMP3(dst, data, tc)
+		if err != nil {
+			return dst, 0, err
+		}
+
+	default:
+		return dst, 0, fmt.Errorf("webmplayer: unsupported audio codec: %s", d.codec)
+	}
+
+	sampleCount := (len(dst) - origLen) / 2
+	d.timecode = tc + time.Duration(sampleCount)*time.Second/time.Duration(d.samplingFrequency)
+	if sampleCount > 0 {
+		// The first Vorbis packet after a reset has no samples, and its timecode doesn't continue to the next packet.
+		d.continuous = true
+	}
+	return dst, tc, nil
+}
+
+// appendSilence appends n silent stereo samples to dst.
+func appendSilence(dst []float32, n int) []float32 {
+	if n <= 0 {
+		return dst
+	}
+	return append(dst, make([]float32, 2*n)...)
+}
+
+// concealOpus appends n samples per channel to dst to conceal lost or damaged packets.
+// If next is not nil, the last lost frame is recovered from the forward error correction data in next, the packet after the lost packets.
+func (d *AudioDecoder) concealOpus(dst []float32, n int, next []byte) []float32 {
+	// The duration to conceal must be a multiple of 2.5 ms.
+	step := d.samplingFrequency / 400
+	n -= n % step
+	maxSamples := len(d.opPCM) / d.channels
+	maxSamples -= maxSamples % step
+	for n > 0 {
+		m := min(n, maxSamples)
+		var sampleCount int
+		if m == n && next != nil {
+			sampleCount = d.opDecoder.DecodeFloat(next, d.opPCM[:m*d.channels], 1)
+		}
+		if sampleCount <= 0 {
+			sampleCount = d.opDecoder.DecodeFloat(nil, d.opPCM[:m*d.channels], 0)
+		}
+		if sampleCount <= 0 {
+			return dst
+		}
+		dst = d.appendOpusPCM(dst, sampleCount)
+		n -= sampleCount
+	}
+	return dst
+}
+
+// appendOpusPCM appends the first sampleCount samples per channel in d.opPCM to dst as stereo samples.
+func (d *AudioDecoder) appendOpusPCM(dst []float32, sampleCount int) []float32 {
+	pcm := d.opPCM[:sampleCount*d.channels]
+	if d.opGain != 1 {
+		for i := range pcm {
+			pcm[i] *= d.opGain
+		}
+	}
+	if d.downmix != nil {
+		return d.downmix.appendInterleaved(dst, pcm)
+	}
+	origLen := len(dst)
+	dst = append(dst, pcm...)
+	if d.channels == 1 {
+		allocstats.Add(allocstats.AudioDecode, 4*sampleCount)
+		dst = append(dst, make([]float32, sampleCount)...)
+		frames := dst[origLen:]
+		for i := sampleCount - 1; i >= 0; i-- {
+			frames[2*i] = frames[i]
+			frames[2*i+1] = frames[i]
+		}
+	}
+	return dst
+}
+
+// appendFLAC decodes the FLAC frame and appends the stereo samples to dst.
+func (d *AudioDecoder) appendFLAC(dst []float32, data []byte) ([]float32, error) {
+	samples, bps, err := d.flacDecoder.Decode(data)
+	if err != nil {
+		return dst, err
+	}
+	if len(samples) != d.channels {
+		return dst, fmt.Errorf("webmplayer: the FLAC frame has %d channels, but the track has %d", len(samples), d.channels)
+	}
+	scale := 1 / float32(int64(1)<<(bps-1))
+	if len(d.flacPCM) < len(samples) {
+		d.flacPCM = make([][]float32, len(samples))
+	}
+	pcm := d.flacPCM[:len(samples)]
+	for ch, s := range samples {
+		pcm[ch] = pcm[ch][:0]
+		for _, v := range s {
+			pcm[ch] = append(pcm[ch], float32(v)*scale)
+		}
+	}
+	switch d.channels {
+	case 1:
+		for _, v := range pcm[0] {
+			dst = append(dst, v, v)
+		}
+	case 2:
+		for i := range pcm[0] {
+			dst = append(dst, pcm[0][i], pcm[1][i])
+		}
+	default:
+		dst = d.downmix.appendPlanar(dst, pcm)
+	}
+	return dst, nil
+}
+
+// Reset resets the decoder state to decode from the given timecode after seeking.
+func (d *AudioDecoder) Reset(timecode time.Duration) error {
+	d.timecode = timecode
+	d.continuous = false
+	switch d.codec {
+	case AudioCodecVorbis:
+		if err := libvorbis.SynthesisRestart(d.voDSP); err != nil {
+			return fmt.Errorf("webmplayer: libvorbis.SynthesisRestart failed: %w", err)
+		}
+	case AudioCodecOpus:
+		d.opSkip = 0
+		if timecode == 0 {
+			// The stream is decoded from the start again.
+			d.opSkip = d.opPreSkip
+		}
+		if err := d.opDecoder.ResetState(); err != nil {
+			return fmt.Errorf("webmplayer: libopus.MSDecoder.ResetState failed: %w", err)
+		}
+	case AudioCodecMP3:
+		// go-mp3 cannot be reset, so create a new decoder for the next packet.
+		d.mp3Decoder = nil
+		d.mp3Source.data = d.mp3Source.data[:0]
+		d.mp3Skip = timecode != 0
+	}
+	return nil
+}
+
+// Close releases the resources of the decoder. The decoder must not be used after Close.
+func (d *AudioDecoder) Close() {
+	if d.voBlock != nil {
+		libvorbis.BlockClear(d.voBlock)
+		d.voBlock = nil
+	}
+	if d.voDSP != nil {
+		libvorbis.DspClear(d.voDSP)
+		d.voDSP = nil
+	}
+	if d.voInfo != nil {
+		libvorbis.InfoClear(d.voInfo)
+		d.voInfo = nil
+	}
+	if d.opDecoder != nil {
+		d.opDecoder.Destroy()
+		d.opDecoder = nil
+	}
+	d.mp3Decoder = nil
+	d.flacDecoder = nil
+}
+
+func readVorbisCodecPrivate(codecPrivate []byte) (*libvorbis.Info, *libvorbis.Comment, error) {
+	if len(codecPrivate) < 1 {
+		return nil, nil, errors.New("webmplayer: codec private data is too short")
+	}
+
+	p := codecPrivate
+
+	// https://www.matroska.org/technical/codec_specs.html
+	// > Byte 1: number of distinct packets #p minus one inside the CodecPrivate block. This MUST be “2” for current (as of 2016-07-08) Vorbis headers.
+	if p[0] != 0x02 {
+		return nil, nil, fmt.Errorf("webmplayer: wrong codec private data for Vorbis: %d", p[0])
+	}
+	offset := 1
+	p = p[1:]
+
+	headers := make([][]byte, 3)
+	var size0, size1 int
+
+	// https://xiph.org/vorbis/doc/framing.html
+	// > The raw packet is logically divided into [n] 255 byte segments and a last fractional segment of < 255 bytes.
+	// > A packet size may well consist only of the trailing fractional segment, and a fractional segment may be zero length.
+	// > These values, called "lacing values" are then saved and placed into the header segment table.
+	for i := 0; i < 2; i++ {
+		for (p[0] == 0xff) && offset < len(codecPrivate) {
+			if i == 0 {
+				size0 += 0xff
+			} else {
+				size1 += 0xff
+			}
+			offset++
+			p = p[1:]
+		}
+		if offset >= len(codecPrivate)-1 {
+			return nil, nil, errors.New("webmplayer: header sizes damaged")
+		}
+		if i == 0 {
+			size0 += int(p[0])
+		} else {
+			size1 += int(p[0])
+		}
+		offset++
+		p = p[1:]
+	}
+	headers[0] = codecPrivate[offset : offset+size0]
+	headers[1] = codecPrivate[offset+size0 : offset+size0+size1]
+	headers[2] = codecPrivate[offset+size0+size1:]
+
+	info := libvorbis.InfoInit()
+	comment := libvorbis.CommentInit()
+
+	for i := 0; i < 3; i++ {
+		packet := &libvorbis.OggPacket{
+			Packet: headers[i],
+			BOS:    i == 0,
+		}
+		if err := libvorbis.SynthesisHeaderin(info, comment, packet); err != nil {
+			return nil, nil, fmt.Errorf("webmplayer: libvorbis.SynthesisHeaderin failed: %w", err)
+		}
+	}
+
+	return info, comment, nil
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package codec
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer/internal/libopus"
+	"github.com/hajimehoshi/webmplayer/internal/webm"
+)
+
+// The Opus packets in testdata are encoded from sine waves at 48 kHz:
+//
+//   - mono_20ms_*.opus are three consecutive 20 ms packets of a mono stream, each of which has one frame.
+//   - stereo_60ms.opus is a 60 ms packet of a stereo stream, which has three 20 ms frames.
+
+func readTestOpusPacket(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// newTestOpusDecoder creates a decoder for an Opus track at 48 kHz without the pre-skip, so that the samples can be counted simply.
+func newTestOpusDecoder(t *testing.T, channels int) *AudioDecoder {
+	t.Helper()
+	head := make([]byte, 19)
+	copy(head, "OpusHead")
+	head[8] = 1
+	head[9] = byte(channels)
+	binary.LittleEndian.PutUint32(head[12:16], 48000)
+	d, err := NewAudioDecoder(&webm.TrackEntry{
+		CodecID:      string(AudioCodecOpus),
+		CodecPrivate: head,
+		Audio: webm.Audio{
+			SamplingFrequency: 48000,
+			Channels:          uint(channels),
+		},
+	}, func(err error) {
+		t.Error(err)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(d.Close)
+	return d
+}
+
+func TestDecodeOpusMultiFramePacket(t *testing.T) {
+	data := readTestOpusPacket(t, "stereo_60ms.opus")
+	if got, want := OpusPacketSamples(data), 2880; got != want {
+		t.Fatalf("OpusPacketSamples: got: %d, want: %d", got, want)
+	}
+
+	d := newTestOpusDecoder(t, 2)
+	for i, timecode := range []time.Duration{0, 60 * time.Millisecond} {
+		samples, tc, err := d.Decode(nil, data, timecode, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// All the frames in the packet are decoded.
+		if got, want := len(samples)/2, 2880; got != want {
+			t.Errorf("packet %d: samples: got: %d, want: %d", i, got, want)
+		}
+		if tc != timecode {
+			t.Errorf("packet %d: timecode: got: %v, want: %v", i, tc, timecode)
+		}
+	}
+}
+
+func TestDecodeOpusLacedBlock(t *testing.T) {
+	// The reader splits a laced block into packets, and only the first packet has the block's timecode.
+	timecodes := []time.Duration{100 * time.Millisecond, webm.BadTC, webm.BadTC}
+
+	d := newTestOpusDecoder(t, 1)
+	for i, timecode := range timecodes {
+		data := readTestOpusPacket(t, []string{"mono_20ms_0.opus", "mono_20ms_1.opus", "mono_20ms_2.opus"}[i])
+		samples, tc, err := d.Decode(nil, data, timecode, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(samples)/2, 960; got != want {
+			t.Errorf("packet %d: samples: got: %d, want: %d", i, got, want)
+		}
+		if want := 100*time.Millisecond + time.Duration(i)*20*time.Millisecond; tc != want {
+			t.Errorf("packet %d: timecode: got: %v, want: %v", i, tc, want)
+		}
+	}
+}
+
+func TestDecodeOpusMonoUpmix(t *testing.T) {
+	data := readTestOpusPacket(t, "mono_20ms_1.opus")
+
+	// Decode the packet as mono with libopus directly to compare.
+	mono, err := libopus.DecoderCreate(48000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mono.Destroy()
+	want := make([]float32, 960)
+	if n := mono.DecodeFloat(data, want, 0); n != len(want) {
+		t.Fatalf("DecodeFloat: got: %d, want: %d", n, len(want))
+	}
+
+	d := newTestOpusDecoder(t, 1)
+	samples, _, err := d.Decode(nil, data, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(samples) / 2; got != len(want) {
+		t.Fatalf("samples: got: %d, want: %d", got, len(want))
+	}
+	// Every sample including the first one is copied to both the channels.
+	for i, v := range want {
+		if samples[2*i] != v || samples[2*i+1] != v {
+			t.Errorf("sample %d: got: (%v, %v), want: (%v, %v)", i, samples[2*i], samples[2*i+1], v, v)
+		}
+	}
+	if want[0] == want[1] {
+		t.Errorf("the first two samples must differ to check the first sample: %v", want[0])
+	}
+}
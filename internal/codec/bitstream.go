@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package codec
+
+// IsKeyframe reports whether the frame starting with the given byte is a keyframe.
+func IsKeyframe(codec VideoCodec, b byte) bool {
+	switch codec {
+	case VideoCodecVP8:
+		// https://datatracker.ietf.org/doc/html/rfc6386#section-9.1
+		return b&0x01 == 0
+	case VideoCodecVP9:
+		// frame_marker (2 bits), profile_low_bit, profile_high_bit, [reserved_zero if profile is 3], show_existing_frame and frame_type.
+		bit := 4
+		if b>>4&0x3 == 0x3 {
+			bit++
+		}
+		if b&(0x80>>bit) != 0 {
+			// A frame showing an existing frame.
+			return false
+		}
+		return b&(0x80>>(bit+1)) == 0
+	}
+	return false
+}
+
+// IsNonReferenceFrame reports whether the frame is shown and doesn't update any reference frame,
+// so that the frame can be skipped without breaking the following frames.
+//
+// Only the VP9 uncompressed header is checked, as VP8 has the reference updates in the compressed header.
+func IsNonReferenceFrame(codec VideoCodec, data []byte) bool {
+	if codec != VideoCodecVP9 || len(data) < 3 {
+		return false
+	}
+	if data[len(data)-1]&0xe0 == 0xc0 {
+		// A superframe, which typically has a hidden frame to update a reference frame.
+		return false
+	}
+
+	// https://storage.googleapis.com/downloads.webmproject.org/docs/vp9/vp9-bitstream-specification-v0.6-20160331-draft.pdf
+	var pos int
+	read := func(n int) int {
+		var v int
+		for range n {
+			v = v<<1 | int(data[pos/8]>>(7-pos%8)&1)
+			pos++
+		}
+		return v
+	}
+	// frame_marker, profile_low_bit and profile_high_bit.
+	read(2)
+	profile := read(1)
+	profile |= read(1) << 1
+	if profile == 3 {
+		read(1)
+	}
+	if read(1) == 1 {
+		// show_existing_frame.
+		return false
+	}
+	frameType := read(1)
+	showFrame := read(1)
+	errorResilientMode := read(1)
+	if frameType == 0 || showFrame == 0 {
+		// A keyframe refreshes all the reference frames, and a hidden frame is always a reference.
+		return false
+	}
+	if errorResilientMode == 0 {
+		// reset_frame_context.
+		read(2)
+	}
+	// intra_only is 0 for a shown frame, and refresh_frame_flags follows.
+	return read(8) == 0
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package codec
+
+import (
+	"fmt"
+	"math"
+)
+
+// speaker is a loudspeaker position of a channel.
+type speaker int
+
+const (
+	speakerFrontLeft speaker = iota
+	speakerFrontRight
+	speakerFrontCenter
+	speakerLFE
+	speakerBackLeft
+	speakerBackRight
+	speakerBackCenter
+	speakerSideLeft
+	speakerSideRight
+)
+
+// vorbisChannelLayouts is the speaker positions of the channels in the decoder order, indexed by the channel counts.
+// Opus with the channel mapping family 1 uses the same order.
+//
+// https://xiph.org/vorbis/doc/Vorbis_I_spec.html#x1-810004.3.9
+var vorbisChannelLayouts = [...][]speaker{
+	3: {speakerFrontLeft, speakerFrontCenter, speakerFrontRight},
+	4: {speakerFrontLeft, speakerFrontRight, speakerBackLeft, speakerBackRight},
+	5: {speakerFrontLeft, speakerFrontCenter, speakerFrontRight, speakerBackLeft, speakerBackRight},
+	6: {speakerFrontLeft, speakerFrontCenter, speakerFrontRight, speakerBackLeft, speakerBackRight, speakerLFE},
+	7: {speakerFrontLeft, speakerFrontCenter, speakerFrontRight, speakerSideLeft, speakerSideRight, speakerBackCenter, speakerLFE},
+	8: {speakerFrontLeft, speakerFrontCenter, speakerFrontRight, speakerSideLeft, speakerSideRight, speakerBackLeft, speakerBackRight, speakerLFE},
+}
+
+// waveChannelLayouts is the speaker positions of the channels in the order of WAVE's default channel masks, indexed by the channel counts.
+// Uncompressed PCM and FLAC use this order.
+var waveChannelLayouts = [...][]speaker{
+	3: {speakerFrontLeft, speakerFrontRight, speakerFrontCenter},
+	4: {speakerFrontLeft, speakerFrontRight, speakerBackLeft, speakerBackRight},
+	5: {speakerFrontLeft, speakerFrontRight, speakerFrontCenter, speakerBackLeft, speakerBackRight},
+	6: {speakerFrontLeft, speakerFrontRight, speakerFrontCenter, speakerLFE, speakerBackLeft, speakerBackRight},
+	7: {speakerFrontLeft, speakerFrontRight, speakerFrontCenter, speakerLFE, speakerBackCenter, speakerSideLeft, speakerSideRight},
+	8: {speakerFrontLeft, speakerFrontRight, speakerFrontCenter, speakerLFE, speakerBackLeft, speakerBackRight, speakerSideLeft, speakerSideRight},
+}
+
+// stereoGains returns the gains of the speaker for the left and the right outputs, based on ITU-R BS.775.
+// The LFE channel is dropped as usual.
+func (s speaker) stereoGains() (float32, float32) {
+	const g = math.Sqrt2 / 2
+	switch s {
+	case speakerFrontLeft:
+		return 1, 0
+	case speakerFrontRight:
+		return 0, 1
+	case speakerFrontCenter, speakerBackCenter:
+		return g, g
+	case speakerBackLeft, speakerSideLeft:
+		return g, 0
+	case speakerBackRight, speakerSideRight:
+		return 0, g
+	default:
+		return 0, 0
+	}
+}
+
+// downmixer mixes the channels of a surround track down to stereo.
+type downmixer struct {
+	// gains is the interleaved left and right gains of the channels.
+	gains []float32
+}
+
+// newDownmixer creates a downmixer for the channels in the order of layouts, which is vorbisChannelLayouts or waveChannelLayouts.
+func newDownmixer(channels int, layouts [][]speaker) (*downmixer, error) {
+	if channels < 3 || channels >= len(layouts) {
+		return nil, fmt.Errorf("webmplayer: unsupported channel count: %d", channels)
+	}
+	d := &downmixer{
+		gains: make([]float32, 2*channels),
+	}
+	// Normalize the gains so that the output doesn't clip.
+	var sum float32
+	for i, s := range layouts[channels] {
+		l, r := s.stereoGains()
+		d.gains[2*i] = l
+		d.gains[2*i+1] = r
+		sum += l
+	}
+	for i := range d.gains {
+		d.gains[i] /= sum
+	}
+	return d, nil
+}
+
+// appendPlanar appends the stereo frames mixed from the planar samples pcm, indexed by channels, to dst.
+func (d *downmixer) appendPlanar(dst []float32, pcm [][]float32) []float32 {
+	for i := range pcm[0] {
+		var l, r float32
+		for ch := range pcm {
+			v := pcm[ch][i]
+			l += v * d.gains[2*ch]
+			r += v * d.gains[2*ch+1]
+		}
+		dst = append(dst, l, r)
+	}
+	return dst
+}
+
+// appendInterleaved appends the stereo frames mixed from the interleaved samples pcm to dst.
+func (d *downmixer) appendInterleaved(dst []float32, pcm []float32) []float32 {
+	channels := len(d.gains) / 2
+	for i := 0; i+channels <= len(pcm); i += channels {
+		var l, r float32
+		for ch, v := range pcm[i : i+channels] {
+			l += v * d.gains[2*ch]
+			r += v * d.gains[2*ch+1]
+		}
+		dst = append(dst, l, r)
+	}
+	return dst
+}
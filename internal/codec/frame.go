@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package codec
+
+import "image"
+
+// CropBounds returns the given bounds without the given numbers of pixels at the edges.
+// CropBounds returns bounds as it is if nothing would remain.
+func CropBounds(bounds image.Rectangle, left, top, right, bottom int) image.Rectangle {
+	r := image.Rect(bounds.Min.X+left, bounds.Min.Y+top, bounds.Max.X-right, bounds.Max.Y-bottom)
+	if r.Empty() {
+		return bounds
+	}
+	return r
+}
+
+// SubFrame returns the part r of the given frame, which shares the pixels with the given frame.
+func SubFrame(img image.Image, r image.Rectangle) image.Image {
+	switch img := img.(type) {
+	case *image.YCbCr:
+		return img.SubImage(r)
+	case *image.NYCbCrA:
+		return img.SubImage(r)
+	}
+	return img
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package codec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hajimehoshi/go-mp3"
+)
+
+// mp3MaxFrameBytes is the maximum size in bytes of the decoded samples of an MP3 frame,
+// which has 1152 stereo samples of int16.
+const mp3MaxFrameBytes = 1152 * 4
+
+// mp3Source is the source of an MP3 decoder, which has the packets not read by the decoder yet.
+//
+// As go-mp3 decodes a stream from an io.Reader, the packets are queued here, and the decoder reads one frame at a time.
+type mp3Source struct {
+	data []byte
+}
+
+func (m *mp3Source) Read(buf []byte) (int, error) {
+	if len(m.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(buf, m.data)
+	m.data = m.data[n:]
+	return n, nil
+}
+
+// appendMP3 decodes the MP3 frames in the packet and appends the stereo samples to dst.
+// A packet has whole frames in Matroska.
+func (d *AudioDecoder) appendMP3(dst []float32, data []byte, tc time.Duration) ([]float32, time.Duration, error) {
+	d.mp3Source.data = append(d.mp3Source.data, data...)
+
+	if d.mp3Decoder == nil {
+		// go-mp3 reads the first frame when the decoder is created.
+		dec, err := mp3.NewDecoder(&d.mp3Source)
+		if err != nil {
+			return dst, tc, fmt.Errorf("webmplayer: mp3.NewDecoder failed: %w", err)
+		}
+		d.mp3Decoder = dec
+		if dec.SampleRate() != d.samplingFrequency {
+			return dst, tc, fmt.Errorf("webmplayer: the sampling frequency of the MP3 frames (%d) doesn't match the container (%d)", dec.SampleRate(), d.samplingFrequency)
+		}
+		if dst, tc, err = d.readMP3Frame(dst, tc); err != nil {
+			return dst, tc, err
+		}
+	}
+
+	// Read one frame at a time so that the decoder doesn't read beyond the queued packets,
+	// which would lose the state of the previous frame.
+	for len(d.mp3Source.data) > 0 {
+		var err error
+		if dst, tc, err = d.readMP3Frame(dst, tc); err != nil {
+			return dst, tc, err
+		}
+	}
+	return dst, tc, nil
+}
+
+// readMP3Frame appends the stereo samples of the next frame to dst.
+func (d *AudioDecoder) readMP3Frame(dst []float32, tc time.Duration) ([]float32, time.Duration, error) {
+	if d.mp3Buf == nil {
+		d.mp3Buf = make([]byte, mp3MaxFrameBytes)
+	}
+	n, err := d.mp3Decoder.Read(d.mp3Buf)
+	if err != nil {
+		return dst, tc, fmt.Errorf("webmplayer: decoding an MP3 frame failed: %w", err)
+	}
+	if d.mp3Skip {
+		// The first frame after seeking lacks the bit reservoir in the previous frames, and is not decoded correctly.
+		d.mp3Skip = false
+		return dst, tc + time.Duration(n/4)*time.Second/time.Duration(d.samplingFrequency), nil
+	}
+	for i := 0; i+1 < n; i += 2 {
+		dst = append(dst, float32(int16(binary.LittleEndian.Uint16(d.mp3Buf[i:])))/(1<<15))
+	}
+	return dst, tc, nil
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// opusHead is the identification header of an Opus stream.
+//
+// https://www.rfc-editor.org/rfc/rfc7845#section-5.1
+type opusHead struct {
+	channels int
+
+	// preSkip is the number of samples at 48 kHz to discard from the decoder's output at the start of the stream.
+	preSkip int
+
+	// inputSampleRate is the sampling frequency of the original input, which is informational only.
+	inputSampleRate int
+
+	// outputGain is the gain in dB to apply to the decoder's output, which is a Q7.8 fixed-point number in the header.
+	outputGain float64
+
+	// mappingFamily is the channel mapping family. 0 is mono or stereo, and 1 is up to 8 channels in the Vorbis order.
+	mappingFamily int
+
+	streams        int
+	coupledStreams int
+
+	// mapping maps the output channels to the decoded channels.
+	mapping []byte
+}
+
+// parseOpusHead parses an OpusHead in CodecPrivate.
+func parseOpusHead(b []byte) (*opusHead, error) {
+	if len(b) < 19 || !bytes.HasPrefix(b, []byte("OpusHead")) {
+		return nil, fmt.Errorf("webmplayer: invalid OpusHead")
+	}
+	h := &opusHead{
+		channels:        int(b[9]),
+		preSkip:         int(binary.LittleEndian.Uint16(b[10:12])),
+		inputSampleRate: int(binary.LittleEndian.Uint32(b[12:16])),
+		outputGain:      float64(int16(binary.LittleEndian.Uint16(b[16:18]))) / 256,
+		mappingFamily:   int(b[18]),
+	}
+	if h.channels == 0 {
+		return nil, fmt.Errorf("webmplayer: OpusHead has no channels")
+	}
+	if h.mappingFamily == 0 {
+		if h.channels > 2 {
+			return nil, fmt.Errorf("webmplayer: OpusHead has %d channels for the channel mapping family 0", h.channels)
+		}
+		h.streams = 1
+		h.coupledStreams = h.channels - 1
+		h.mapping = []byte{0, 1}[:h.channels]
+		return h, nil
+	}
+
+	if len(b) < 21+h.channels {
+		return nil, fmt.Errorf("webmplayer: OpusHead's channel mapping table is too short")
+	}
+	h.streams = int(b[19])
+	h.coupledStreams = int(b[20])
+	h.mapping = b[21 : 21+h.channels]
+	if h.streams == 0 || h.coupledStreams > h.streams {
+		return nil, fmt.Errorf("webmplayer: invalid Opus stream counts: %d streams and %d coupled streams", h.streams, h.coupledStreams)
+	}
+	return h, nil
+}
+
+// defaultOpusHead returns the header for a mono or stereo stream without an OpusHead.
+func defaultOpusHead(channels int) *opusHead {
+	return &opusHead{
+		channels:       channels,
+		streams:        1,
+		coupledStreams: channels - 1,
+		mapping:        []byte{0, 1}[:channels],
+	}
+}
+
+// OpusPacketSamples returns the number of samples at 48 kHz per channel in the Opus packet, or 0 if the packet is invalid.
+//
+// https://www.rfc-editor.org/rfc/rfc6716#section-3.1
+func OpusPacketSamples(data []byte) int {
+	if len(data) == 0 {
+		return 0
+	}
+	toc := data[0]
+	config := int(toc >> 3)
+	var frameSize int
+	switch {
+	case config < 12:
+		// SILK: 10, 20, 40 or 60 ms.
+		frameSize = []int{480, 960, 1920, 2880}[config%4]
+	case config < 16:
+		// Hybrid: 10 or 20 ms.
+		frameSize = []int{480, 960}[config%2]
+	default:
+		// CELT: 2.5, 5, 10 or 20 ms.
+		frameSize = []int{120, 240, 480, 960}[config%4]
+	}
+	var frames int
+	switch toc & 0x3 {
+	case 0:
+		frames = 1
+	case 1, 2:
+		frames = 2
+	case 3:
+		if len(data) < 2 {
+			return 0
+		}
+		frames = int(data[1] & 0x3f)
+	}
+	return frames * frameSize
+}
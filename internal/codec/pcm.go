@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package codec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// pcmFormat is the sample format of uncompressed PCM.
+//
+// https://www.matroska.org/technical/codec_specs.html
+type pcmFormat struct {
+	bytesPerSample int
+	bigEndian      bool
+	float          bool
+}
+
+func newPCMFormat(codec AudioCodec, bitDepth int) (pcmFormat, error) {
+	f := pcmFormat{
+		bytesPerSample: bitDepth / 8,
+		bigEndian:      codec == AudioCodecPCMIntBig,
+		float:          codec == AudioCodecPCMFloat,
+	}
+	if f.float {
+		if bitDepth != 32 && bitDepth != 64 {
+			return pcmFormat{}, fmt.Errorf("webmplayer: unsupported bit depth for floating-point PCM: %d", bitDepth)
+		}
+		return f, nil
+	}
+	switch bitDepth {
+	case 8, 16, 24, 32:
+	default:
+		return pcmFormat{}, fmt.Errorf("webmplayer: unsupported bit depth for integer PCM: %d", bitDepth)
+	}
+	return f, nil
+}
+
+// sample returns the sample at the start of b in [-1, 1).
+func (f pcmFormat) sample(b []byte) float32 {
+	if f.float {
+		if f.bytesPerSample == 8 {
+			return float32(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(b))
+	}
+
+	// Read the sample into the upper bits of an int32.
+	var v uint32
+	if f.bigEndian {
+		for i := range f.bytesPerSample {
+			v |= uint32(b[i]) << (24 - 8*i)
+		}
+	} else {
+		for i := range f.bytesPerSample {
+			v |= uint32(b[i]) << (32 - 8*(f.bytesPerSample-i))
+		}
+	}
+	if f.bytesPerSample == 1 {
+		// 8-bit samples are unsigned as in WAVE.
+		v ^= 0x80000000
+	}
+	return float32(int32(v)) / (1 << 31)
+}
+
+// appendPCM appends the stereo samples converted from the uncompressed PCM data to dst.
+func (d *AudioDecoder) appendPCM(dst []float32, data []byte) []float32 {
+	frameSize := d.pcmFormat.bytesPerSample * d.channels
+	frames := len(data) / frameSize
+	d.pcmBuf = d.pcmBuf[:0]
+	for i := range frames * d.channels {
+		d.pcmBuf = append(d.pcmBuf, d.pcmFormat.sample(data[i*d.pcmFormat.bytesPerSample:]))
+	}
+	switch d.channels {
+	case 1:
+		for _, v := range d.pcmBuf {
+			dst = append(dst, v, v)
+		}
+		return dst
+	case 2:
+		return append(dst, d.pcmBuf...)
+	default:
+		return d.downmix.appendInterleaved(dst, d.pcmBuf)
+	}
+}
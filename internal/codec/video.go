@@ -0,0 +1,371 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package codec
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"unsafe"
+
+	"github.com/hajimehoshi/webmplayer/internal/allocstats"
+	"github.com/hajimehoshi/webmplayer/internal/libvpx"
+)
+
+type VideoCodec string
+
+const (
+	VideoCodecVP8  VideoCodec = "V_VP8"
+	VideoCodecVP9  VideoCodec = "V_VP9"
+	VideoCodecVP10 VideoCodec = "V_VP10"
+	VideoCodecAV1  VideoCodec = "V_AV1"
+)
+
+// VideoDecoderOptions represents options for video decoders.
+type VideoDecoderOptions struct {
+	// Threads is the number of threads for decoding. 0 means the default.
+	Threads int
+
+	// Pool is the pool of the frame buffers. If Pool is nil, new buffers are allocated for each frame.
+	Pool *FramePool
+
+	// VP8Postproc is the postprocessing of VP8 frames. nil disables postprocessing.
+	VP8Postproc *VP8Postproc
+}
+
+// VP8Postproc represents the postprocessing of VP8 frames.
+type VP8Postproc struct {
+	Deblock           bool
+	DemacroblockLevel int
+	NoiseLevel        int
+}
+
+// VideoDecoder decodes video packets synchronously.
+type VideoDecoder struct {
+	decoder  *libvpx.Decoder
+	vpxCodec libvpx.Codec
+	codec    VideoCodec
+	options  VideoDecoderOptions
+
+	// alpha decodes the alpha channel stored in BlockAdditional data. alpha is created lazily.
+	alpha *VideoDecoder
+
+	// alphaDecoded reports whether the last packet had an alpha channel.
+	alphaDecoded bool
+
+	// colorSpace and colorRange are the bitstream's color space and range of the last frame.
+	colorSpace libvpx.ColorSpace
+	colorRange libvpx.ColorRange
+
+	// skipLoopFilter indicates whether the loop filter is skipped. This is kept to apply it again after Reset.
+	skipLoopFilter bool
+}
+
+// FrameIter is an iterator of decoded frames.
+type FrameIter struct {
+	iter      libvpx.Iter
+	alphaIter libvpx.Iter
+}
+
+func NewVideoDecoder(codec VideoCodec, options *VideoDecoderOptions) (*VideoDecoder, error) {
+	if options == nil {
+		options = &VideoDecoderOptions{}
+	}
+	d := &VideoDecoder{
+		codec:   codec,
+		options: *options,
+	}
+	switch codec {
+	case VideoCodecVP8:
+		d.vpxCodec = libvpx.CodecVP8
+	case VideoCodecVP9:
+		d.vpxCodec = libvpx.CodecVP9
+	case VideoCodecAV1:
+		// The built-in decoder is libvpx only. AV1 is decoded by a decoder from a VideoDecoderFactory.
+		return nil, fmt.Errorf("webmplayer: the built-in video decoder doesn't support AV1; decode AV1 with a VideoDecoderFactory")
+	default:
+		return nil, fmt.Errorf("webmplayer: unsupported VPX codec: %s", codec)
+	}
+	if err := d.init(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *VideoDecoder) init() error {
+	pp := d.options.VP8Postproc
+	if d.codec != VideoCodecVP8 {
+		pp = nil
+	}
+	decoder, err := libvpx.DecoderCreate(d.vpxCodec, d.options.Threads, pp != nil)
+	if err != nil {
+		return err
+	}
+	d.decoder = decoder
+	if pp != nil {
+		var ppFlags int
+		if pp.Deblock {
+			ppFlags |= libvpx.Deblock
+		}
+		if pp.DemacroblockLevel > 0 {
+			ppFlags |= libvpx.Demacroblock
+		}
+		if pp.NoiseLevel > 0 {
+			ppFlags |= libvpx.AddNoise
+		}
+		if err := d.decoder.SetPostproc(ppFlags, pp.DemacroblockLevel, pp.NoiseLevel); err != nil {
+			return fmt.Errorf("webmplayer: setting the VP8 postprocessing failed: %w", err)
+		}
+	}
+	if d.skipLoopFilter {
+		if err := d.decoder.SetSkipLoopFilter(true); err != nil {
+			return fmt.Errorf("webmplayer: skipping the loop filter failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetSkipLoopFilter sets whether the loop filter is skipped to reduce the decoding time.
+// The frames get blocky, and the artifacts propagate to the following frames until the next keyframe.
+//
+// SetSkipLoopFilter does nothing for codecs other than VP9.
+func (d *VideoDecoder) SetSkipLoopFilter(skip bool) error {
+	if d.codec != VideoCodecVP9 || d.skipLoopFilter == skip {
+		return nil
+	}
+	if err := d.decoder.SetSkipLoopFilter(skip); err != nil {
+		return fmt.Errorf("webmplayer: skipping the loop filter failed: %w", err)
+	}
+	d.skipLoopFilter = skip
+	if d.alpha != nil {
+		return d.alpha.SetSkipLoopFilter(skip)
+	}
+	return nil
+}
+
+// Reset recreates the decoder's context, which discards the state of the previous frames, e.g. after a corrupt packet.
+// The next packet must be a keyframe.
+func (d *VideoDecoder) Reset() error {
+	if err := d.decoder.Destroy(); err != nil {
+		return err
+	}
+	if err := d.init(); err != nil {
+		return err
+	}
+	d.alphaDecoded = false
+	if d.alpha != nil {
+		if err := d.alpha.Reset(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close destroys the decoder's context.
+func (d *VideoDecoder) Close() error {
+	if d.alpha != nil {
+		if err := d.alpha.Close(); err != nil {
+			return err
+		}
+	}
+	return d.decoder.Destroy()
+}
+
+// ColorSpace returns the bitstream's color space and range of the last frame.
+func (d *VideoDecoder) ColorSpace() (libvpx.ColorSpace, libvpx.ColorRange) {
+	return d.colorSpace, d.colorRange
+}
+
+// Decode decodes a packet.
+// additional is the BlockAdditional data of the packet, which is an alpha channel encoded as a luma plane. additional can be nil.
+func (d *VideoDecoder) Decode(data []byte, additional []byte) error {
+	if err := d.decoder.Decode(data); err != nil {
+		return err
+	}
+
+	d.alphaDecoded = false
+	if len(additional) == 0 {
+		return nil
+	}
+	if d.alpha == nil {
+		alpha, err := NewVideoDecoder(d.codec, &d.options)
+		if err != nil {
+			return err
+		}
+		if err := alpha.SetSkipLoopFilter(d.skipLoopFilter); err != nil {
+			return err
+		}
+		d.alpha = alpha
+	}
+	if err := d.alpha.Decode(additional, nil); err != nil {
+		return fmt.Errorf("webmplayer: decoding the alpha channel failed: %w", err)
+	}
+	d.alphaDecoded = true
+	return nil
+}
+
+// NextFrame returns the next decoded frame, or nil if there is no more frame.
+//
+// The returned image is an *image.YCbCr, or an *image.NYCbCrA if the packet has an alpha channel.
+func (d *VideoDecoder) NextFrame(iter *FrameIter) image.Image {
+	img, ok := d.decoder.NextImage(&iter.iter)
+	if !ok {
+		return nil
+	}
+	d.colorSpace = img.ColorSpace
+	d.colorRange = img.ColorRange
+	frame := yCbCrFromImage(&img, d.options.Pool)
+	if !d.alphaDecoded {
+		return frame
+	}
+
+	a, ok := d.alpha.decoder.NextImage(&iter.alphaIter)
+	if !ok || a.Width != img.Width || a.Height != img.Height {
+		return frame
+	}
+	alpha, stride := copyPlane(&a, libvpx.PlaneY, a.Width, a.Height, d.options.Pool)
+	return &image.NYCbCrA{
+		YCbCr:   *frame,
+		A:       alpha,
+		AStride: stride,
+	}
+}
+
+// yCbCrFromImage copies the planes of the given image into buffers from pool.
+// A high bit depth image is converted to 8-bit.
+func yCbCrFromImage(img *libvpx.Image, pool *FramePool) *image.YCbCr {
+	w, h := img.Width, img.Height
+	xShift, yShift := img.XChromaShift, img.YChromaShift
+	cw := (w + xShift) >> xShift
+	ch := (h + yShift) >> yShift
+
+	var ratio image.YCbCrSubsampleRatio
+	switch {
+	case xShift == 1 && yShift == 1:
+		ratio = image.YCbCrSubsampleRatio420
+	case xShift == 1 && yShift == 0:
+		ratio = image.YCbCrSubsampleRatio422
+	case xShift == 0 && yShift == 1:
+		ratio = image.YCbCrSubsampleRatio440
+	default:
+		ratio = image.YCbCrSubsampleRatio444
+	}
+
+	y, yStride := copyPlane(img, libvpx.PlaneY, w, h, pool)
+	cb, cStride := copyPlane(img, libvpx.PlaneU, cw, ch, pool)
+	cr, _ := copyPlane(img, libvpx.PlaneV, cw, ch, pool)
+	return &image.YCbCr{
+		Y:              y,
+		Cb:             cb,
+		Cr:             cr,
+		YStride:        yStride,
+		CStride:        cStride,
+		SubsampleRatio: ratio,
+		Rect:           image.Rect(0, 0, w, h),
+	}
+}
+
+// FramePool reuses the pixel buffers of decoded frames, so that the steady-state playback doesn't allocate them.
+type FramePool struct {
+	bufs [][]byte
+	m    sync.Mutex
+}
+
+// maxPooledBuffers is the maximum number of buffers kept in a FramePool.
+const maxPooledBuffers = 16
+
+// get returns a buffer of size n. The content is undefined.
+// If p is nil, get allocates a new buffer.
+func (p *FramePool) get(n int) []byte {
+	if p == nil {
+		allocstats.Add(allocstats.VideoDecode, n)
+		return make([]byte, n)
+	}
+	p.m.Lock()
+	defer p.m.Unlock()
+	for i, b := range p.bufs {
+		if cap(b) < n {
+			continue
+		}
+		last := len(p.bufs) - 1
+		p.bufs[i] = p.bufs[last]
+		p.bufs[last] = nil
+		p.bufs = p.bufs[:last]
+		return b[:n]
+	}
+	allocstats.Add(allocstats.VideoDecode, n)
+	return make([]byte, n)
+}
+
+// Put returns the buffers of the frame from the pool. The frame must not be used after Put.
+// If p is nil, Put does nothing.
+func (p *FramePool) Put(frame image.Image) {
+	if p == nil {
+		return
+	}
+	var bufs [4][]byte
+	switch f := frame.(type) {
+	case *image.YCbCr:
+		bufs = [4][]byte{f.Y, f.Cb, f.Cr}
+	case *image.NYCbCrA:
+		bufs = [4][]byte{f.Y, f.Cb, f.Cr, f.A}
+	default:
+		return
+	}
+	p.m.Lock()
+	defer p.m.Unlock()
+	for _, b := range bufs {
+		if b == nil || len(p.bufs) >= maxPooledBuffers {
+			continue
+		}
+		p.bufs = append(p.bufs, b)
+	}
+}
+
+// Clear discards the pooled buffers.
+// If p is nil, Clear does nothing.
+func (p *FramePool) Clear() {
+	if p == nil {
+		return
+	}
+	p.m.Lock()
+	defer p.m.Unlock()
+	clear(p.bufs)
+	p.bufs = p.bufs[:0]
+}
+
+// bayer4x4 is a 4x4 ordered dither matrix.
+var bayer4x4 = [4][4]int{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// copyPlane copies the plane of the given image with the size (w, h) into a buffer from pool, and returns the 8-bit pixels and the stride.
+//
+// A high bit depth plane (e.g. VP9 profile 2) is converted to 8-bit with ordered dithering to avoid banding.
+func copyPlane(img *libvpx.Image, plane int, w, h int, pool *FramePool) ([]byte, int) {
+	stride := img.Strides[plane]
+	if !img.HighBitDepth {
+		dst := pool.get(stride * h)
+		copy(dst, img.Planes[plane][:stride*h])
+		return dst, stride
+	}
+
+	shift := img.BitDepth - 8
+	src := unsafe.Slice((*uint16)(unsafe.Pointer(unsafe.SliceData(img.Planes[plane]))), stride/2*h)
+	// Align the stride so that the plane can be uploaded without copying.
+	dstStride := (w + 3) &^ 3
+	dst := pool.get(dstStride * h)
+	for j := 0; j < h; j++ {
+		srcRow := src[stride/2*j : stride/2*j+w]
+		dstRow := dst[dstStride*j : dstStride*j+w]
+		for i, v := range srcRow {
+			v := (int(v) + (bayer4x4[j&3][i&3]<<shift)/16) >> shift
+			dstRow[i] = uint8(min(v, 0xff))
+		}
+	}
+	return dst, dstStride
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+// Package demux implements the demuxers of WebM, Ogg and IVF.
+package demux
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer/internal/webm"
+)
+
+// Demuxer reads packets from a container.
+//
+// Demuxer works in the same way as webm.Reader.
+// A packet without a track number and with webm.BadTC as its timecode is sent at the end of the stream.
+// A packet without a track number and with a valid timecode is sent after seeking.
+type Demuxer interface {
+	// Packets returns the channel of packets, which is closed after Shutdown.
+	Packets() <-chan webm.Packet
+
+	// Seek seeks to a position at or before t, from which decoders can start decoding.
+	Seek(t time.Duration)
+
+	// Shutdown stops the demuxer.
+	Shutdown()
+
+	// Err returns the error that stopped reading the stream, e.g. an I/O error or a corrupt page, or nil.
+	// The stream ends at the error, so Err is valid after the packet at the end of the stream is received.
+	Err() error
+}
+
+// Indexer is implemented by a Demuxer that can find keyframes without cue points.
+type Indexer interface {
+	// KeyframeBefore returns the position of the last keyframe at or before t.
+	// KeyframeBefore returns false if the stream has neither cue points nor a complete index by BuildIndex.
+	KeyframeBefore(t time.Duration) (time.Duration, bool)
+
+	// BuildIndex indexes the keyframes by reading the whole stream again, if the stream has no cue points.
+	BuildIndex(ctx context.Context) error
+}
+
+// LiveDemuxer is implemented by a Demuxer that can read live streams.
+type LiveDemuxer interface {
+	// IsLive reports whether the stream is a live stream, which has neither a size nor a duration, and might continue indefinitely.
+	// A live stream can't be sought, as the clusters are not indexed.
+	IsLive() bool
+}
+
+// demuxerShutdown is a special seek position to stop a demuxer.
+const demuxerShutdown = time.Duration(math.MinInt64)
+
+// New detects the container of r, and parses its headers into meta.
+//
+// meta is filled for a non-WebM container too, so that its tracks can be treated in the same way as WebM tracks.
+func New(r io.ReadSeeker, meta *webm.WebM) (Demuxer, error) {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	switch string(magic[:]) {
+	case "OggS":
+		return newOggDemuxer(r, meta)
+	case "DKIF":
+		return newIVFDemuxer(r, meta)
+	}
+
+	reader, err := webm.Parse(r, meta)
+	if err != nil {
+		return nil, err
+	}
+	return &webmDemuxer{
+		reader: reader,
+		meta:   meta,
+		source: r,
+		start:  start,
+	}, nil
+}
+
+type webmDemuxer struct {
+	reader *webm.Reader
+	meta   *webm.WebM
+
+	// source is the stream from start, which BuildIndex reads again if it is an io.ReaderAt.
+	source io.ReadSeeker
+	start  int64
+}
+
+func (w *webmDemuxer) Packets() <-chan webm.Packet {
+	return w.reader.Chan
+}
+
+// Seek seeks to the last cue point at or before t.
+// Without cue points, Seek seeks to the cluster of the last known keyframe at or before t.
+//
+// The reader seeks to the first indexed position at or after the given position,
+// so the position must be exactly a known cue point or cluster to start before t.
+func (w *webmDemuxer) Seek(t time.Duration) {
+	pos, ok := CueBefore(w.meta, t)
+	if !ok {
+		_, pos, _ = w.reader.KeyframeBefore(t)
+	}
+	w.reader.Seek(pos)
+}
+
+// KeyframeBefore returns the position of the last keyframe at or before t.
+// KeyframeBefore returns false if the stream has neither cue points nor a complete index by BuildIndex.
+func (w *webmDemuxer) KeyframeBefore(t time.Duration) (time.Duration, bool) {
+	if pos, ok := CueBefore(w.meta, t); ok {
+		return pos, true
+	}
+	if !w.reader.IsIndexed() {
+		// The known keyframes might not include the last one before t.
+		return 0, false
+	}
+	pos, _, ok := w.reader.KeyframeBefore(t)
+	return pos, ok
+}
+
+// BuildIndex indexes the keyframes by reading the whole stream again, if the stream has no cue points.
+func (w *webmDemuxer) BuildIndex(ctx context.Context) error {
+	if len(w.meta.Cues.CuePoint) > 0 || w.reader.IsIndexed() || w.reader.IsLive() {
+		return nil
+	}
+	ra, ok := w.source.(io.ReaderAt)
+	if !ok {
+		return fmt.Errorf("webmplayer: a stream without cue points must be an io.ReaderAt to build an index")
+	}
+	r := io.NewSectionReader(ra, 0, math.MaxInt64)
+	if _, err := r.Seek(w.start, io.SeekStart); err != nil {
+		return err
+	}
+	return w.reader.BuildIndex(ctx, r)
+}
+
+// IsLive reports whether the segment has an unknown size, like a live stream.
+func (w *webmDemuxer) IsLive() bool {
+	return w.reader.IsLive()
+}
+
+func (w *webmDemuxer) Shutdown() {
+	w.reader.Shutdown()
+}
+
+// Err returns nil, as the WebM reader doesn't report errors.
+func (w *webmDemuxer) Err() error {
+	return nil
+}
+
+// CueBefore returns the position of the last cue point at or before t.
+// CueBefore returns false if there are no cue points.
+func CueBefore(meta *webm.WebM, t time.Duration) (time.Duration, bool) {
+	cues := meta.Cues.CuePoint
+	if len(cues) == 0 {
+		return 0, false
+	}
+	var pos time.Duration
+	for _, c := range cues {
+		// The reader treats cue times as milliseconds, regardless of the timecode scale.
+		ct := time.Duration(c.CueTime) * time.Millisecond
+		if ct <= t && ct > pos {
+			pos = ct
+		}
+	}
+	return pos, true
+}
+
+// DisplaySize returns the size to display the video track's frames, with the pixel aspect ratio applied.
+func DisplaySize(video *webm.Video) (int, int) {
+	// The size after cropping.
+	pw := max(int(video.PixelWidth)-int(video.PixelCropLeft)-int(video.PixelCropRight), 0)
+	ph := max(int(video.PixelHeight)-int(video.PixelCropTop)-int(video.PixelCropBottom), 0)
+
+	dw, dh := int(video.DisplayWidth), int(video.DisplayHeight)
+	// The parser sets the pixel size to the display size when the display size is absent,
+	// while the default display size is the size after cropping.
+	if dw == 0 || dh == 0 || (dw == int(video.PixelWidth) && dh == int(video.PixelHeight)) {
+		return pw, ph
+	}
+	// https://www.matroska.org/technical/elements.html#DisplayUnit
+	if video.DisplayUnit == 0 {
+		return dw, dh
+	}
+	// DisplayWidth and DisplayHeight are in centimeters, inches or only an aspect ratio.
+	// Keep the pixel height and adjust the width to the aspect ratio.
+	return int(math.Round(float64(ph) * float64(dw) / float64(dh))), ph
+}
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package demux
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer/internal/allocstats"
+	"github.com/hajimehoshi/webmplayer/internal/codec"
+	"github.com/hajimehoshi/webmplayer/internal/webm"
+)
+
+// ivfTrackNumber is the track number of the video track of an IVF file.
+const ivfTrackNumber = 1
+
+// ivfDemuxer demuxes an IVF file, which has raw VP8 or VP9 frames, as a video track.
+type ivfDemuxer struct {
+	r      io.ReadSeeker
+	frames []ivfFrame
+
+	ch   chan webm.Packet
+	seek chan time.Duration
+
+	// err is the error that stopped reading the stream.
+	err atomic.Pointer[error]
+}
+
+type ivfFrame struct {
+	offset   int64
+	size     int
+	pts      time.Duration
+	keyframe bool
+}
+
+func newIVFDemuxer(r io.ReadSeeker, meta *webm.WebM) (*ivfDemuxer, error) {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	// https://wiki.multimedia.cx/index.php/Duck_IVF
+	var header [32]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	headerSize := int64(binary.LittleEndian.Uint16(header[6:8]))
+	fourcc := string(header[8:12])
+	width := uint(binary.LittleEndian.Uint16(header[12:14]))
+	height := uint(binary.LittleEndian.Uint16(header[14:16]))
+	rate := int64(binary.LittleEndian.Uint32(header[16:20]))
+	scale := int64(binary.LittleEndian.Uint32(header[20:24]))
+	if rate == 0 || scale == 0 {
+		return nil, fmt.Errorf("webmplayer: invalid IVF time base: %d/%d", scale, rate)
+	}
+
+	var vcodec codec.VideoCodec
+	switch fourcc {
+	case "VP80":
+		vcodec = codec.VideoCodecVP8
+	case "VP90":
+		vcodec = codec.VideoCodecVP9
+	case "AV01":
+		vcodec = codec.VideoCodecAV1
+	default:
+		return nil, fmt.Errorf("webmplayer: unsupported IVF FourCC: %q", fourcc)
+	}
+
+	d := &ivfDemuxer{
+		r:    r,
+		ch:   make(chan webm.Packet, 4),
+		seek: make(chan time.Duration, 4),
+	}
+
+	// Scan the frame headers to build an index, as IVF has no index.
+	offset := start + headerSize
+	for {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		// The frame header and the first byte of the frame to determine whether the frame is a keyframe.
+		var frameHeader [13]byte
+		if _, err := io.ReadFull(r, frameHeader[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				// A truncated frame at the end is ignored.
+				break
+			}
+			return nil, err
+		}
+		size := int(binary.LittleEndian.Uint32(frameHeader[0:4]))
+		if size == 0 {
+			offset += 12
+			continue
+		}
+		pts := int64(binary.LittleEndian.Uint64(frameHeader[4:12]))
+		d.frames = append(d.frames, ivfFrame{
+			offset:   offset + 12,
+			size:     size,
+			pts:      time.Duration(pts * scale * int64(time.Second) / rate),
+			keyframe: codec.IsKeyframe(vcodec, frameHeader[12]),
+		})
+		offset += 12 + int64(size)
+	}
+
+	meta.Segment.Tracks.TrackEntry = []webm.TrackEntry{
+		{
+			TrackNumber: ivfTrackNumber,
+			TrackType:   uint(webm.TrackTypeVideo),
+			CodecID:     string(vcodec),
+			Video: webm.Video{
+				PixelWidth:    width,
+				PixelHeight:   height,
+				DisplayWidth:  width,
+				DisplayHeight: height,
+			},
+		},
+	}
+	meta.Segment.SegmentInformation.TimecodeScale = 1
+	if len(d.frames) > 0 {
+		frameDuration := time.Duration(scale * int64(time.Second) / rate)
+		meta.Segment.SegmentInformation.Duration = float64(d.frames[len(d.frames)-1].pts + frameDuration)
+	}
+
+	go d.loop()
+
+	return d, nil
+}
+
+func (d *ivfDemuxer) Packets() <-chan webm.Packet {
+	return d.ch
+}
+
+func (d *ivfDemuxer) Seek(t time.Duration) {
+	d.seek <- t
+}
+
+func (d *ivfDemuxer) Shutdown() {
+	d.seek <- demuxerShutdown
+}
+
+// Err returns the error that stopped reading the stream, or nil if the stream ended normally.
+func (d *ivfDemuxer) Err() error {
+	if err := d.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (d *ivfDemuxer) loop() {
+	defer close(d.ch)
+
+	// next is the index of the next frame.
+	var next int
+	for {
+		seek := webm.BadTC
+		for len(d.seek) != 0 {
+			seek = <-d.seek
+		}
+
+		if seek == webm.BadTC {
+			// The stream ends at an error.
+			if next < len(d.frames) && d.err.Load() == nil {
+				f := d.frames[next]
+				next++
+				allocstats.Add(allocstats.Demux, f.size)
+				data := make([]byte, f.size)
+				if _, err := d.r.Seek(f.offset, io.SeekStart); err != nil {
+					d.err.Store(&err)
+					next = len(d.frames)
+					continue
+				}
+				if _, err := io.ReadFull(d.r, data); err != nil {
+					d.err.Store(&err)
+					next = len(d.frames)
+					continue
+				}
+				d.ch <- webm.Packet{
+					Data:        data,
+					Timecode:    f.pts,
+					TrackNumber: ivfTrackNumber,
+					Keyframe:    f.keyframe,
+				}
+				continue
+			}
+			d.ch <- webm.Packet{
+				Timecode: webm.BadTC,
+			}
+			seek = <-d.seek
+		}
+
+		if seek == demuxerShutdown {
+			return
+		}
+		next = d.keyframeIndexBefore(seek)
+		d.ch <- webm.Packet{
+			Timecode: seek,
+		}
+	}
+}
+
+// keyframeIndexBefore returns the index of the last keyframe at or before t.
+func (d *ivfDemuxer) keyframeIndexBefore(t time.Duration) int {
+	i := sort.Search(len(d.frames), func(i int) bool {
+		return d.frames[i].pts > t
+	})
+	for i--; i > 0; i-- {
+		if d.frames[i].keyframe {
+			return i
+		}
+	}
+	return 0
+}
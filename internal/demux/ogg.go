@@ -0,0 +1,330 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package demux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer/internal/allocstats"
+	"github.com/hajimehoshi/webmplayer/internal/codec"
+	"github.com/hajimehoshi/webmplayer/internal/ogg"
+	"github.com/hajimehoshi/webmplayer/internal/webm"
+)
+
+// oggTrackNumber is the track number of the audio track of an Ogg file.
+const oggTrackNumber = 1
+
+// oggDemuxer demuxes the first Vorbis or Opus logical bitstream of an Ogg file as an audio track.
+//
+// Other logical bitstreams like Theora are ignored, as there is no decoder for them.
+type oggDemuxer struct {
+	reader *ogg.Reader
+	serial uint32
+
+	// samplingFrequency is the rate of granule positions.
+	samplingFrequency int
+
+	// dataOffset is the position of the first page after the headers.
+	dataOffset int64
+
+	// pages is the pages with granule positions, which are used for seeking.
+	pages []oggPage
+
+	// preroll is the number of samples to decode before a seek target.
+	preroll int64
+
+	// opus indicates whether the bitstream is Opus, whose end is trimmed by the last granule position.
+	opus bool
+
+	ch   chan webm.Packet
+	seek chan time.Duration
+
+	// err is the error that stopped reading the stream.
+	err atomic.Pointer[error]
+}
+
+type oggPage struct {
+	offset  int64
+	granule int64
+}
+
+func newOggDemuxer(r io.ReadSeeker, meta *webm.WebM) (*oggDemuxer, error) {
+	reader, err := ogg.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	d := &oggDemuxer{
+		reader: reader,
+		ch:     make(chan webm.Packet, 4),
+		seek:   make(chan time.Duration, 4),
+	}
+
+	var track *webm.TrackEntry
+	var headers [][]byte
+	var headerCount int
+	var preSkip int64
+	for track == nil || len(headers) < headerCount {
+		p, err := reader.NextPacket()
+		if errors.Is(err, io.EOF) {
+			if track == nil {
+				return nil, fmt.Errorf("webmplayer: no Vorbis or Opus stream found in the Ogg file")
+			}
+			return nil, fmt.Errorf("webmplayer: Ogg headers are truncated")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if track != nil {
+			if p.Serial == d.serial {
+				headers = append(headers, p.Data)
+			}
+			continue
+		}
+		if !p.BOS {
+			continue
+		}
+
+		switch {
+		case bytes.HasPrefix(p.Data, []byte("OpusHead")):
+			// https://www.rfc-editor.org/rfc/rfc7845#section-5.1
+			if len(p.Data) < 19 {
+				return nil, fmt.Errorf("webmplayer: OpusHead is too short")
+			}
+			track = &webm.TrackEntry{
+				CodecID:      string(codec.AudioCodecOpus),
+				CodecPrivate: p.Data,
+				Audio: webm.Audio{
+					// Opus is always decoded at 48kHz regardless of the input sample rate.
+					SamplingFrequency: 48000,
+					Channels:          uint(p.Data[9]),
+				},
+			}
+			preSkip = int64(binary.LittleEndian.Uint16(p.Data[10:12]))
+			// OpusTags follows.
+			headerCount = 2
+			// 80ms is recommended to converge the decoder state.
+			d.preroll = 48000 * 80 / 1000
+			d.opus = true
+
+		case bytes.HasPrefix(p.Data, []byte("\x01vorbis")):
+			// https://xiph.org/vorbis/doc/Vorbis_I_spec.html#x1-630004.2.2
+			if len(p.Data) < 16 {
+				return nil, fmt.Errorf("webmplayer: Vorbis identification header is too short")
+			}
+			track = &webm.TrackEntry{
+				CodecID: string(codec.AudioCodecVorbis),
+				Audio: webm.Audio{
+					SamplingFrequency: float64(binary.LittleEndian.Uint32(p.Data[12:16])),
+					Channels:          uint(p.Data[11]),
+				},
+			}
+			// The comment and setup headers follow.
+			headerCount = 3
+
+		default:
+			continue
+		}
+		d.serial = p.Serial
+		headers = append(headers, p.Data)
+	}
+
+	if track.CodecID == string(codec.AudioCodecVorbis) {
+		track.CodecPrivate = xiphLace(headers)
+	}
+	track.TrackNumber = oggTrackNumber
+	track.TrackType = uint(webm.TrackTypeAudio)
+	d.samplingFrequency = int(track.SamplingFrequency)
+	if d.samplingFrequency <= 0 {
+		return nil, fmt.Errorf("webmplayer: invalid sample rate: %d", d.samplingFrequency)
+	}
+
+	// Audio data starts on a fresh page after the headers.
+	d.dataOffset = reader.Offset()
+
+	// Scan the pages to build a seek index, as Ogg has no index.
+	// Only the page headers are read.
+	for {
+		page, err := reader.NextPage(true)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if page.Serial != d.serial || page.Granule < 0 {
+			continue
+		}
+		d.pages = append(d.pages, oggPage{
+			offset:  page.Offset,
+			granule: page.Granule,
+		})
+	}
+	if err := reader.SeekPage(d.dataOffset); err != nil {
+		return nil, err
+	}
+
+	meta.Segment.Tracks.TrackEntry = []webm.TrackEntry{*track}
+	meta.Segment.SegmentInformation.TimecodeScale = 1
+	if len(d.pages) > 0 {
+		meta.Segment.SegmentInformation.Duration = float64(d.granuleToTime(d.pages[len(d.pages)-1].granule - preSkip))
+	}
+
+	go d.loop()
+
+	return d, nil
+}
+
+// xiphLace packs the headers in the same way as Vorbis's CodecPrivate in Matroska.
+func xiphLace(headers [][]byte) []byte {
+	b := []byte{byte(len(headers) - 1)}
+	for _, h := range headers[:len(headers)-1] {
+		n := len(h)
+		for ; n >= 0xff; n -= 0xff {
+			b = append(b, 0xff)
+		}
+		b = append(b, byte(n))
+	}
+	for _, h := range headers {
+		b = append(b, h...)
+	}
+	return b
+}
+
+func (d *oggDemuxer) granuleToTime(granule int64) time.Duration {
+	sf := int64(d.samplingFrequency)
+	return time.Duration(granule/sf)*time.Second + time.Duration(granule%sf)*time.Second/time.Duration(sf)
+}
+
+func (d *oggDemuxer) timeToGranule(t time.Duration) int64 {
+	sf := int64(d.samplingFrequency)
+	return int64(t/time.Second)*sf + int64(t%time.Second)*sf/int64(time.Second)
+}
+
+func (d *oggDemuxer) Packets() <-chan webm.Packet {
+	return d.ch
+}
+
+func (d *oggDemuxer) Seek(t time.Duration) {
+	d.seek <- t
+}
+
+func (d *oggDemuxer) Shutdown() {
+	d.seek <- demuxerShutdown
+}
+
+// Err returns the error that stopped reading the stream, or nil if the stream ended normally.
+func (d *oggDemuxer) Err() error {
+	if err := d.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// nextPacket returns the next packet.
+// After an error, nextPacket keeps returning the error, as the position of the reader is unknown.
+func (d *oggDemuxer) nextPacket() (ogg.Packet, error) {
+	if err := d.Err(); err != nil {
+		return ogg.Packet{}, err
+	}
+	p, err := d.reader.NextPacket()
+	if err != nil && !errors.Is(err, io.EOF) {
+		d.err.Store(&err)
+	}
+	return p, err
+}
+
+func (d *oggDemuxer) loop() {
+	defer close(d.ch)
+
+	// timecode is the timecode of the next packet, or webm.BadTC if the packet doesn't start at a granule position.
+	timecode := time.Duration(0)
+	// granule is the granule position at the start of the next packet, counted from the last granule position for Opus.
+	var granule int64
+	// lastGranule is the granule position of the end of the stream.
+	lastGranule := int64(-1)
+	if len(d.pages) > 0 {
+		lastGranule = d.pages[len(d.pages)-1].granule
+	}
+	for {
+		seek := webm.BadTC
+		for len(d.seek) != 0 {
+			seek = <-d.seek
+		}
+
+		if seek == webm.BadTC {
+			p, err := d.nextPacket()
+			if err == nil {
+				if p.Serial != d.serial || len(p.Data) == 0 {
+					continue
+				}
+				// The reader allocates the data of each packet.
+				allocstats.Add(allocstats.Demux, len(p.Data))
+				pkt := webm.Packet{
+					Data:        p.Data,
+					Timecode:    timecode,
+					TrackNumber: oggTrackNumber,
+					Keyframe:    true,
+				}
+				if d.opus {
+					granule += int64(codec.OpusPacketSamples(p.Data))
+					if p.Granule >= 0 && p.Granule == lastGranule && granule > p.Granule {
+						// The last granule position trims the padding at the end of the stream.
+						pkt.DiscardPadding = d.granuleToTime(granule - p.Granule)
+					}
+				}
+				d.ch <- pkt
+				timecode = webm.BadTC
+				if p.Granule >= 0 {
+					timecode = d.granuleToTime(p.Granule)
+					granule = p.Granule
+				}
+				continue
+			}
+			d.ch <- webm.Packet{
+				Timecode: webm.BadTC,
+			}
+			seek = <-d.seek
+		}
+
+		if seek == demuxerShutdown {
+			return
+		}
+		g, err := d.seekTo(seek)
+		if err != nil {
+			d.err.Store(&err)
+		}
+		granule = g
+		timecode = d.granuleToTime(granule)
+		d.ch <- webm.Packet{
+			Timecode: seek,
+		}
+	}
+}
+
+// seekTo moves the reader to a page before t, and returns the granule position at the start of the next packet.
+func (d *oggDemuxer) seekTo(t time.Duration) (int64, error) {
+	granule := d.timeToGranule(t) - d.preroll
+	// Find the last page whose granule position is before the target.
+	i := sort.Search(len(d.pages), func(i int) bool {
+		return d.pages[i].granule >= granule
+	}) - 1
+	if i < 0 {
+		if err := d.reader.SeekPage(d.dataOffset); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+	if err := d.reader.SeekAfterPage(d.pages[i].offset); err != nil {
+		return 0, err
+	}
+	return d.pages[i].granule, nil
+}
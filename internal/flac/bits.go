@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package flac
+
+import (
+	"math/bits"
+)
+
+// bitReader reads bits from the most significant bit of each byte.
+type bitReader struct {
+	data []byte
+	// pos is the position in bits.
+	pos int
+}
+
+// read reads an unsigned integer of n bits, where n is at most 57.
+func (r *bitReader) read(n int) (uint64, error) {
+	if r.pos+n > 8*len(r.data) {
+		return 0, errShortFrame
+	}
+	var v uint64
+	for n > 0 {
+		offset := r.pos & 7
+		m := min(8-offset, n)
+		b := r.data[r.pos>>3] >> (8 - offset - m) & (1<<m - 1)
+		v = v<<m | uint64(b)
+		n -= m
+		r.pos += m
+	}
+	return v, nil
+}
+
+// readSigned reads a two's complement integer of n bits, where n is at most 32.
+func (r *bitReader) readSigned(n int) (int32, error) {
+	if n == 0 {
+		return 0, nil
+	}
+	v, err := r.read(n)
+	if err != nil {
+		return 0, err
+	}
+	return int32(int64(v<<(64-n)) >> (64 - n)), nil
+}
+
+// readUnary reads the number of zero bits before a one bit.
+func (r *bitReader) readUnary() (uint64, error) {
+	var n uint64
+	for r.pos < 8*len(r.data) {
+		offset := r.pos & 7
+		b := r.data[r.pos>>3] << offset
+		if b == 0 {
+			n += uint64(8 - offset)
+			r.pos += 8 - offset
+			continue
+		}
+		z := bits.LeadingZeros8(b)
+		n += uint64(z)
+		r.pos += z + 1
+		return n, nil
+	}
+	return 0, errShortFrame
+}
+
+var (
+	crc8Table  [256]byte
+	crc16Table [256]uint16
+)
+
+func init() {
+	// The polynomials are x^8 + x^2 + x + 1 and x^16 + x^15 + x^2 + 1.
+	for i := range 256 {
+		c8 := byte(i)
+		c16 := uint16(i) << 8
+		for range 8 {
+			if c8&0x80 != 0 {
+				c8 = c8<<1 ^ 0x07
+			} else {
+				c8 <<= 1
+			}
+			if c16&0x8000 != 0 {
+				c16 = c16<<1 ^ 0x8005
+			} else {
+				c16 <<= 1
+			}
+		}
+		crc8Table[i] = c8
+		crc16Table[i] = c16
+	}
+}
+
+func crc8(data []byte) byte {
+	var c byte
+	for _, b := range data {
+		c = crc8Table[c^b]
+	}
+	return c
+}
+
+func crc16(data []byte) uint16 {
+	var c uint16
+	for _, b := range data {
+		c = c<<8 ^ crc16Table[byte(c>>8)^b]
+	}
+	return c
+}
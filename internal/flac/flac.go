@@ -0,0 +1,462 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+// Package flac implements a minimal decoder of FLAC frames.
+//
+// See RFC 9639 for the format.
+package flac
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// maxBitsPerSample is the maximum bits per sample supported.
+// The side channel of a stereo frame has one more bit, which still fits in int32.
+const maxBitsPerSample = 24
+
+var errShortFrame = errors.New("flac: frame is too short")
+
+// StreamInfo is the STREAMINFO metadata block.
+type StreamInfo struct {
+	MaxBlockSize  int
+	SampleRate    int
+	Channels      int
+	BitsPerSample int
+}
+
+// ParseStreamInfo parses the STREAMINFO block in the header of a FLAC stream,
+// which starts with the "fLaC" marker followed by the metadata blocks.
+func ParseStreamInfo(header []byte) (*StreamInfo, error) {
+	if len(header) < 4 || string(header[:4]) != "fLaC" {
+		return nil, fmt.Errorf("flac: invalid stream marker")
+	}
+	b := header[4:]
+	for len(b) >= 4 {
+		last := b[0]&0x80 != 0
+		typ := b[0] & 0x7f
+		size := int(b[1])<<16 | int(b[2])<<8 | int(b[3])
+		b = b[4:]
+		if len(b) < size {
+			return nil, fmt.Errorf("flac: metadata block is too short")
+		}
+		if typ == 0 {
+			if size < 34 {
+				return nil, fmt.Errorf("flac: STREAMINFO is too short: %d", size)
+			}
+			v := binary.BigEndian.Uint64(b[10:18])
+			info := &StreamInfo{
+				MaxBlockSize:  int(binary.BigEndian.Uint16(b[2:4])),
+				SampleRate:    int(v >> 44),
+				Channels:      int(v>>41&0x7) + 1,
+				BitsPerSample: int(v>>36&0x1f) + 1,
+			}
+			if info.SampleRate == 0 {
+				return nil, fmt.Errorf("flac: invalid sample rate in STREAMINFO")
+			}
+			return info, nil
+		}
+		if last {
+			break
+		}
+		b = b[size:]
+	}
+	return nil, fmt.Errorf("flac: STREAMINFO not found")
+}
+
+// Decoder decodes FLAC frames.
+type Decoder struct {
+	info StreamInfo
+
+	// samples is the decoded samples indexed by channels.
+	samples [][]int32
+}
+
+// NewDecoder creates a decoder for the stream.
+func NewDecoder(info *StreamInfo) *Decoder {
+	return &Decoder{
+		info: *info,
+	}
+}
+
+// Decode decodes a frame.
+//
+// Decode returns the samples indexed by channels, and the bits per sample of the frame.
+// The samples are valid until the next Decode call.
+func (d *Decoder) Decode(frame []byte) ([][]int32, int, error) {
+	if len(frame) < 2 || crc16(frame[:len(frame)-2]) != binary.BigEndian.Uint16(frame[len(frame)-2:]) {
+		return nil, 0, fmt.Errorf("flac: frame CRC mismatch")
+	}
+
+	r := &bitReader{data: frame[:len(frame)-2]}
+	h, err := d.readHeader(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if cap(d.samples) < h.channels {
+		d.samples = make([][]int32, h.channels)
+	}
+	d.samples = d.samples[:h.channels]
+	for ch := range d.samples {
+		if cap(d.samples[ch]) < h.blockSize {
+			d.samples[ch] = make([]int32, h.blockSize)
+		}
+		d.samples[ch] = d.samples[ch][:h.blockSize]
+
+		bps := h.bitsPerSample
+		switch {
+		case h.assignment == assignmentLeftSide && ch == 1,
+			h.assignment == assignmentSideRight && ch == 0,
+			h.assignment == assignmentMidSide && ch == 1:
+			bps++
+		}
+		if err := readSubframe(r, d.samples[ch], bps); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	switch h.assignment {
+	case assignmentLeftSide:
+		left, side := d.samples[0], d.samples[1]
+		for i := range side {
+			side[i] = left[i] - side[i]
+		}
+	case assignmentSideRight:
+		side, right := d.samples[0], d.samples[1]
+		for i := range side {
+			side[i] += right[i]
+		}
+	case assignmentMidSide:
+		mid, side := d.samples[0], d.samples[1]
+		for i := range mid {
+			m := mid[i]<<1 | side[i]&1
+			mid[i] = (m + side[i]) >> 1
+			side[i] = (m - side[i]) >> 1
+		}
+	}
+	return d.samples, h.bitsPerSample, nil
+}
+
+type channelAssignment int
+
+const (
+	assignmentIndependent channelAssignment = iota
+	assignmentLeftSide
+	assignmentSideRight
+	assignmentMidSide
+)
+
+type frameHeader struct {
+	blockSize     int
+	channels      int
+	assignment    channelAssignment
+	bitsPerSample int
+}
+
+func (d *Decoder) readHeader(r *bitReader) (*frameHeader, error) {
+	sync, err := r.read(15)
+	if err != nil {
+		return nil, err
+	}
+	if sync != 0x7ffc {
+		return nil, fmt.Errorf("flac: invalid frame sync code")
+	}
+	// Skip the blocking strategy.
+	if _, err := r.read(1); err != nil {
+		return nil, err
+	}
+	v, err := r.read(16)
+	if err != nil {
+		return nil, err
+	}
+	blockSizeCode := int(v >> 12)
+	sampleRateCode := int(v >> 8 & 0xf)
+	channelCode := int(v >> 4 & 0xf)
+	sampleSizeCode := int(v >> 1 & 0x7)
+
+	// Skip the frame or sample number, coded like UTF-8.
+	b, err := r.read(8)
+	if err != nil {
+		return nil, err
+	}
+	for b&0x80 != 0 && b&0x40 != 0 {
+		if _, err := r.read(8); err != nil {
+			return nil, err
+		}
+		b <<= 1
+	}
+
+	h := &frameHeader{}
+	switch {
+	case blockSizeCode == 1:
+		h.blockSize = 192
+	case blockSizeCode >= 2 && blockSizeCode <= 5:
+		h.blockSize = 576 << (blockSizeCode - 2)
+	case blockSizeCode == 6:
+		n, err := r.read(8)
+		if err != nil {
+			return nil, err
+		}
+		h.blockSize = int(n) + 1
+	case blockSizeCode == 7:
+		n, err := r.read(16)
+		if err != nil {
+			return nil, err
+		}
+		h.blockSize = int(n) + 1
+	case blockSizeCode >= 8:
+		h.blockSize = 256 << (blockSizeCode - 8)
+	default:
+		return nil, fmt.Errorf("flac: reserved block size")
+	}
+
+	// The sample rate is not used as a track has a fixed sample rate.
+	switch sampleRateCode {
+	case 12:
+		if _, err := r.read(8); err != nil {
+			return nil, err
+		}
+	case 13, 14:
+		if _, err := r.read(16); err != nil {
+			return nil, err
+		}
+	case 15:
+		return nil, fmt.Errorf("flac: invalid sample rate")
+	}
+
+	switch {
+	case channelCode < 8:
+		h.channels = channelCode + 1
+		h.assignment = assignmentIndependent
+	case channelCode <= 10:
+		h.channels = 2
+		h.assignment = channelAssignment(channelCode - 7)
+	default:
+		return nil, fmt.Errorf("flac: reserved channel assignment")
+	}
+
+	switch sampleSizeCode {
+	case 0:
+		h.bitsPerSample = d.info.BitsPerSample
+	case 1:
+		h.bitsPerSample = 8
+	case 2:
+		h.bitsPerSample = 12
+	case 4:
+		h.bitsPerSample = 16
+	case 5:
+		h.bitsPerSample = 20
+	case 6:
+		h.bitsPerSample = 24
+	case 7:
+		h.bitsPerSample = 32
+	default:
+		return nil, fmt.Errorf("flac: reserved sample size")
+	}
+	if h.bitsPerSample > maxBitsPerSample {
+		return nil, fmt.Errorf("flac: unsupported bits per sample: %d", h.bitsPerSample)
+	}
+
+	// The header is followed by its CRC-8.
+	end := r.pos / 8
+	crc, err := r.read(8)
+	if err != nil {
+		return nil, err
+	}
+	if crc8(r.data[:end]) != byte(crc) {
+		return nil, fmt.Errorf("flac: frame header CRC mismatch")
+	}
+	return h, nil
+}
+
+// fixedCoefficients is the coefficients of the fixed predictors, indexed by the orders.
+var fixedCoefficients = [...][]int32{
+	{},
+	{1},
+	{2, -1},
+	{3, -3, 1},
+	{4, -6, 4, -1},
+}
+
+func readSubframe(r *bitReader, samples []int32, bps int) error {
+	v, err := r.read(8)
+	if err != nil {
+		return err
+	}
+	if v&0x80 != 0 {
+		return fmt.Errorf("flac: invalid subframe padding")
+	}
+	typ := int(v >> 1 & 0x3f)
+
+	var wasted int
+	if v&1 != 0 {
+		n, err := r.readUnary()
+		if err != nil {
+			return err
+		}
+		wasted = int(n) + 1
+		if wasted >= bps {
+			return fmt.Errorf("flac: too many wasted bits: %d", wasted)
+		}
+		bps -= wasted
+	}
+
+	switch {
+	case typ == 0:
+		s, err := r.readSigned(bps)
+		if err != nil {
+			return err
+		}
+		for i := range samples {
+			samples[i] = s
+		}
+	case typ == 1:
+		for i := range samples {
+			s, err := r.readSigned(bps)
+			if err != nil {
+				return err
+			}
+			samples[i] = s
+		}
+	case typ >= 8 && typ <= 12:
+		order := typ - 8
+		if err := readWarmup(r, samples, order, bps); err != nil {
+			return err
+		}
+		if err := readResidual(r, samples, order); err != nil {
+			return err
+		}
+		predict(samples, fixedCoefficients[order], 0)
+	case typ >= 32:
+		order := typ - 31
+		if err := readWarmup(r, samples, order, bps); err != nil {
+			return err
+		}
+		p, err := r.read(4)
+		if err != nil {
+			return err
+		}
+		if p == 0xf {
+			return fmt.Errorf("flac: invalid LPC precision")
+		}
+		precision := int(p) + 1
+		shift, err := r.readSigned(5)
+		if err != nil {
+			return err
+		}
+		if shift < 0 {
+			return fmt.Errorf("flac: negative LPC shift")
+		}
+		coefs := make([]int32, order)
+		for i := range coefs {
+			c, err := r.readSigned(precision)
+			if err != nil {
+				return err
+			}
+			coefs[i] = c
+		}
+		if err := readResidual(r, samples, order); err != nil {
+			return err
+		}
+		predict(samples, coefs, int(shift))
+	default:
+		return fmt.Errorf("flac: reserved subframe type: %d", typ)
+	}
+
+	if wasted > 0 {
+		for i := range samples {
+			samples[i] <<= wasted
+		}
+	}
+	return nil
+}
+
+func readWarmup(r *bitReader, samples []int32, order, bps int) error {
+	if order > len(samples) {
+		return fmt.Errorf("flac: predictor order %d exceeds the block size %d", order, len(samples))
+	}
+	for i := range order {
+		s, err := r.readSigned(bps)
+		if err != nil {
+			return err
+		}
+		samples[i] = s
+	}
+	return nil
+}
+
+// readResidual reads the Rice-coded residual into samples after the warm-up samples.
+func readResidual(r *bitReader, samples []int32, order int) error {
+	method, err := r.read(2)
+	if err != nil {
+		return err
+	}
+	var paramBits int
+	switch method {
+	case 0:
+		paramBits = 4
+	case 1:
+		paramBits = 5
+	default:
+		return fmt.Errorf("flac: reserved residual coding method")
+	}
+	escape := uint64(1)<<paramBits - 1
+
+	po, err := r.read(4)
+	if err != nil {
+		return err
+	}
+	partitions := 1 << po
+	if len(samples)%partitions != 0 || len(samples)/partitions < order {
+		return fmt.Errorf("flac: invalid partition order: %d", po)
+	}
+
+	i := order
+	for p := range partitions {
+		end := (p + 1) * len(samples) / partitions
+		param, err := r.read(paramBits)
+		if err != nil {
+			return err
+		}
+		if param == escape {
+			n, err := r.read(5)
+			if err != nil {
+				return err
+			}
+			for ; i < end; i++ {
+				s, err := r.readSigned(int(n))
+				if err != nil {
+					return err
+				}
+				samples[i] = s
+			}
+			continue
+		}
+		for ; i < end; i++ {
+			q, err := r.readUnary()
+			if err != nil {
+				return err
+			}
+			low, err := r.read(int(param))
+			if err != nil {
+				return err
+			}
+			u := q<<param | low
+			samples[i] = int32(u>>1) ^ -int32(u&1)
+		}
+	}
+	return nil
+}
+
+// predict restores samples from the residual after the warm-up samples by the linear prediction.
+func predict(samples []int32, coefs []int32, shift int) {
+	for i := len(coefs); i < len(samples); i++ {
+		var sum int64
+		for j, c := range coefs {
+			sum += int64(c) * int64(samples[i-1-j])
+		}
+		samples[i] += int32(sum >> shift)
+	}
+}
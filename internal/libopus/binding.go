@@ -8,6 +8,15 @@ package libopus
 // #cgo CFLAGS: -DOPUS_BUILD -DUSE_ALLOCA -DHAVE_LRINT -DHAVE_LRINTF
 //
 // #include "opus.h"
+// #include "opus_multistream.h"
+//
+// static int opus_decoder_reset_state(OpusDecoder* st) {
+//   return opus_decoder_ctl(st, OPUS_RESET_STATE);
+// }
+//
+// static int opus_multistream_decoder_reset_state(OpusMSDecoder* st) {
+//   return opus_multistream_decoder_ctl(st, OPUS_RESET_STATE);
+// }
 import "C"
 
 import (
@@ -49,7 +58,8 @@ func (e Error) Error() string {
 }
 
 type Decoder struct {
-	decoder *C.OpusDecoder
+	decoder  *C.OpusDecoder
+	channels int
 }
 
 func DecoderCreate(Fs int, channels int) (*Decoder, error) {
@@ -59,17 +69,90 @@ func DecoderCreate(Fs int, channels int) (*Decoder, error) {
 		return nil, Error(err)
 	}
 	return &Decoder{
-		decoder: d,
+		decoder:  d,
+		channels: channels,
 	}, nil
 }
 
+// DecodeFloat decodes a packet into pcm, which has interleaved samples of all the channels.
+// A packet can have multiple frames, and all of them are decoded at once.
+// DecodeFloat returns the number of the decoded samples per channel, or a negative error code.
 func (d *Decoder) DecodeFloat(data []byte, pcm []float32, decodeFec int) int {
 	n := C.opus_decode_float(
 		d.decoder,
 		(*C.uchar)(unsafe.Pointer(unsafe.SliceData(data))),
 		C.opus_int32(len(data)),
 		(*C.float)(unsafe.Pointer(unsafe.SliceData(pcm))),
-		C.int(len(pcm)),
+		// The frame size is the number of samples per channel that pcm can hold.
+		C.int(len(pcm)/d.channels),
+		C.int(decodeFec))
+	return int(n)
+}
+
+// Destroy frees the decoder. The decoder must not be used after Destroy.
+func (d *Decoder) Destroy() {
+	C.opus_decoder_destroy(d.decoder)
+	d.decoder = nil
+}
+
+func (d *Decoder) ResetState() error {
+	if err := C.opus_decoder_reset_state(d.decoder); err != C.OPUS_OK {
+		return Error(err)
+	}
+	return nil
+}
+
+// MSDecoder is a multistream decoder, which decodes a packet with multiple Opus streams, e.g. for surround sound.
+type MSDecoder struct {
+	decoder  *C.OpusMSDecoder
+	channels int
+}
+
+// MSDecoderCreate creates a multistream decoder.
+// mapping maps the output channels to the decoded channels of the streams, and its length must be channels.
+func MSDecoderCreate(Fs int, channels int, streams int, coupledStreams int, mapping []byte) (*MSDecoder, error) {
+	if len(mapping) != channels {
+		return nil, ErrBadArg
+	}
+	var err C.int
+	d := C.opus_multistream_decoder_create(
+		C.opus_int32(Fs),
+		C.int(channels),
+		C.int(streams),
+		C.int(coupledStreams),
+		(*C.uchar)(unsafe.Pointer(unsafe.SliceData(mapping))),
+		&err)
+	if err != C.OPUS_OK {
+		return nil, Error(err)
+	}
+	return &MSDecoder{
+		decoder:  d,
+		channels: channels,
+	}, nil
+}
+
+// DecodeFloat decodes a packet into pcm, which has interleaved samples of all the output channels.
+// DecodeFloat returns the number of the decoded samples per channel, or a negative error code.
+func (d *MSDecoder) DecodeFloat(data []byte, pcm []float32, decodeFec int) int {
+	n := C.opus_multistream_decode_float(
+		d.decoder,
+		(*C.uchar)(unsafe.Pointer(unsafe.SliceData(data))),
+		C.opus_int32(len(data)),
+		(*C.float)(unsafe.Pointer(unsafe.SliceData(pcm))),
+		C.int(len(pcm)/d.channels),
 		C.int(decodeFec))
 	return int(n)
 }
+
+// Destroy frees the decoder. The decoder must not be used after Destroy.
+func (d *MSDecoder) Destroy() {
+	C.opus_multistream_decoder_destroy(d.decoder)
+	d.decoder = nil
+}
+
+func (d *MSDecoder) ResetState() error {
+	if err := C.opus_multistream_decoder_reset_state(d.decoder); err != C.OPUS_OK {
+		return Error(err)
+	}
+	return nil
+}
@@ -200,6 +200,14 @@ func SynthesisPcmout(vd *DspState) [][]float32 {
 	return pcms
 }
 
+func SynthesisRestart(vd *DspState) error {
+	defer runtime.KeepAlive(vd)
+	if ret := C.vorbis_synthesis_restart(vd.c); ret != 0 {
+		return Error(ret)
+	}
+	return nil
+}
+
 func SynthesisRead(vd *DspState, samples int) error {
 	defer runtime.KeepAlive(vd)
 	if ret := C.vorbis_synthesis_read(vd.c, C.int(samples)); ret != 0 {
@@ -222,6 +230,28 @@ func BlockInit(vd *DspState) (*Block, error) {
 	return b, nil
 }
 
+// BlockClear frees the internal data of the block. The block must not be used after BlockClear.
+func BlockClear(vb *Block) {
+	defer runtime.KeepAlive(vb)
+	C.vorbis_block_clear(vb.c)
+}
+
+// DspClear frees the internal data of the DSP state. The state must not be used after DspClear.
+func DspClear(vd *DspState) {
+	defer runtime.KeepAlive(vd)
+	C.vorbis_dsp_clear(vd.c)
+}
+
+// InfoClear frees the internal data of the info. The info must not be used after InfoClear.
+func InfoClear(vi *Info) {
+	C.vorbis_info_clear(&vi.c)
+}
+
+// CommentClear frees the internal data of the comment. The comment must not be used after CommentClear.
+func CommentClear(vc *Comment) {
+	C.vorbis_comment_clear(&vc.c)
+}
+
 func CommentInit() *Comment {
 	var cComment C.vorbis_comment
 	C.vorbis_comment_init(&cComment)
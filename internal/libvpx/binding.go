@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+//go:generate go run gen.go
+
+package libvpx
+
+// #cgo LDFLAGS: -lm
+//
+// #include <stdlib.h>
+//
+// #include "vpx_vpx_decoder.h"
+// #include "vpx_vp8dx.h"
+//
+// static vpx_codec_err_t libvpx_set_postproc(vpx_codec_ctx_t* ctx, int flags, int deblocking_level, int noise_level) {
+//   vp8_postproc_cfg_t cfg = {flags, deblocking_level, noise_level};
+//   return vpx_codec_control(ctx, VP8_SET_POSTPROC, &cfg);
+// }
+//
+// static vpx_codec_err_t libvpx_set_skip_loop_filter(vpx_codec_ctx_t* ctx, int skip) {
+//   return vpx_codec_control(ctx, VP9_SET_SKIP_LOOP_FILTER, skip);
+// }
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+type Error C.vpx_codec_err_t
+
+const (
+	ErrError          Error = C.VPX_CODEC_ERROR
+	ErrMemError       Error = C.VPX_CODEC_MEM_ERROR
+	ErrABIMismatch    Error = C.VPX_CODEC_ABI_MISMATCH
+	ErrIncapable      Error = C.VPX_CODEC_INCAPABLE
+	ErrUnsupBitstream Error = C.VPX_CODEC_UNSUP_BITSTREAM
+	ErrUnsupFeature   Error = C.VPX_CODEC_UNSUP_FEATURE
+	ErrCorruptFrame   Error = C.VPX_CODEC_CORRUPT_FRAME
+	ErrInvalidParam   Error = C.VPX_CODEC_INVALID_PARAM
+	ErrListEnd        Error = C.VPX_CODEC_LIST_END
+)
+
+func (e Error) Error() string {
+	switch e {
+	case ErrError:
+		return "VPX_CODEC_ERROR"
+	case ErrMemError:
+		return "VPX_CODEC_MEM_ERROR"
+	case ErrABIMismatch:
+		return "VPX_CODEC_ABI_MISMATCH"
+	case ErrIncapable:
+		return "VPX_CODEC_INCAPABLE"
+	case ErrUnsupBitstream:
+		return "VPX_CODEC_UNSUP_BITSTREAM"
+	case ErrUnsupFeature:
+		return "VPX_CODEC_UNSUP_FEATURE"
+	case ErrCorruptFrame:
+		return "VPX_CODEC_CORRUPT_FRAME"
+	case ErrInvalidParam:
+		return "VPX_CODEC_INVALID_PARAM"
+	case ErrListEnd:
+		return "VPX_CODEC_LIST_END"
+	default:
+		return fmt.Sprintf("Error(%d)", e)
+	}
+}
+
+func toError(err C.vpx_codec_err_t) error {
+	if err != C.VPX_CODEC_OK {
+		return Error(err)
+	}
+	return nil
+}
+
+// Codec represents a codec of a decoder.
+type Codec int
+
+const (
+	CodecVP8 Codec = iota
+	CodecVP9
+)
+
+// ColorSpace is a color space of a bitstream.
+type ColorSpace C.vpx_color_space_t
+
+const (
+	ColorSpaceUnknown  ColorSpace = C.VPX_CS_UNKNOWN
+	ColorSpaceBt601    ColorSpace = C.VPX_CS_BT_601
+	ColorSpaceBt709    ColorSpace = C.VPX_CS_BT_709
+	ColorSpaceSmpte170 ColorSpace = C.VPX_CS_SMPTE_170
+	ColorSpaceSmpte240 ColorSpace = C.VPX_CS_SMPTE_240
+	ColorSpaceBt2020   ColorSpace = C.VPX_CS_BT_2020
+	ColorSpaceReserved ColorSpace = C.VPX_CS_RESERVED
+	ColorSpaceSrgb     ColorSpace = C.VPX_CS_SRGB
+)
+
+// ColorRange is a color range of a bitstream.
+type ColorRange C.vpx_color_range_t
+
+const (
+	ColorRangeStudio ColorRange = C.VPX_CR_STUDIO_RANGE
+	ColorRangeFull   ColorRange = C.VPX_CR_FULL_RANGE
+)
+
+// Postprocessing flags for SetPostproc.
+const (
+	Deblock      = C.VP8_DEBLOCK
+	Demacroblock = C.VP8_DEMACROBLOCK
+	AddNoise     = C.VP8_ADDNOISE
+)
+
+// Plane indices of Image.
+const (
+	PlaneY = C.VPX_PLANE_Y
+	PlaneU = C.VPX_PLANE_U
+	PlaneV = C.VPX_PLANE_V
+)
+
+// Image is a decoded image.
+// The planes are owned by the decoder, and are valid until the next call of Decode or Destroy.
+type Image struct {
+	// Width and Height are the displayed size.
+	Width  int
+	Height int
+
+	XChromaShift int
+	YChromaShift int
+
+	// HighBitDepth reports whether a sample is stored as a 16-bit value in the native byte order.
+	HighBitDepth bool
+	BitDepth     int
+
+	// Planes are the Y, U and V planes. Strides are in bytes.
+	Planes  [3][]byte
+	Strides [3]int
+
+	ColorSpace ColorSpace
+	ColorRange ColorRange
+}
+
+// Iter is an iterator of decoded images. The zero value starts the iteration.
+type Iter struct {
+	iter C.vpx_codec_iter_t
+}
+
+type Decoder struct {
+	// ctx is allocated in C, as libvpx keeps the pointer to the context.
+	ctx *C.vpx_codec_ctx_t
+}
+
+// DecoderCreate creates a decoder.
+// threads is the maximum number of threads for decoding. 0 means the default.
+// If postproc is true, the decoder is initialized to accept SetPostproc. This is valid only for VP8.
+func DecoderCreate(codec Codec, threads int, postproc bool) (*Decoder, error) {
+	var iface *C.vpx_codec_iface_t
+	switch codec {
+	case CodecVP8:
+		iface = C.vpx_codec_vp8_dx()
+	case CodecVP9:
+		iface = C.vpx_codec_vp9_dx()
+	default:
+		return nil, ErrInvalidParam
+	}
+
+	// libvpx copies the configuration at the initialization.
+	var cfg *C.vpx_codec_dec_cfg_t
+	if threads > 0 {
+		cfg = &C.vpx_codec_dec_cfg_t{
+			threads: C.uint(threads),
+		}
+	}
+	var flags C.vpx_codec_flags_t
+	if postproc {
+		flags |= C.VPX_CODEC_USE_POSTPROC
+	}
+
+	ctx := (*C.vpx_codec_ctx_t)(C.calloc(1, C.size_t(unsafe.Sizeof(C.vpx_codec_ctx_t{}))))
+	if err := C.vpx_codec_dec_init_ver(ctx, iface, cfg, flags, C.VPX_DECODER_ABI_VERSION); err != C.VPX_CODEC_OK {
+		C.free(unsafe.Pointer(ctx))
+		return nil, Error(err)
+	}
+	return &Decoder{
+		ctx: ctx,
+	}, nil
+}
+
+// Destroy frees the decoder. The decoder must not be used after Destroy.
+func (d *Decoder) Destroy() error {
+	err := C.vpx_codec_destroy(d.ctx)
+	C.free(unsafe.Pointer(d.ctx))
+	d.ctx = nil
+	return toError(err)
+}
+
+// Decode decodes a packet. libvpx doesn't retain data.
+func (d *Decoder) Decode(data []byte) error {
+	return toError(C.vpx_codec_decode(d.ctx, (*C.uint8_t)(unsafe.Pointer(unsafe.SliceData(data))), C.uint(len(data)), nil, 0))
+}
+
+// NextImage returns the next decoded image of the last packet, or false if there is no more image.
+func (d *Decoder) NextImage(iter *Iter) (Image, bool) {
+	img := C.vpx_codec_get_frame(d.ctx, &iter.iter)
+	if img == nil {
+		return Image{}, false
+	}
+
+	i := Image{
+		Width:        int(img.d_w),
+		Height:       int(img.d_h),
+		XChromaShift: int(img.x_chroma_shift),
+		YChromaShift: int(img.y_chroma_shift),
+		HighBitDepth: img.fmt&C.VPX_IMG_FMT_HIGHBITDEPTH != 0,
+		BitDepth:     int(img.bit_depth),
+		ColorSpace:   ColorSpace(img.cs),
+		ColorRange:   ColorRange(img._range),
+	}
+	for p := range i.Planes {
+		h := i.Height
+		if p != PlaneY {
+			h = (h + i.YChromaShift) >> i.YChromaShift
+		}
+		stride := int(img.stride[p])
+		i.Planes[p] = unsafe.Slice((*byte)(unsafe.Pointer(img.planes[p])), stride*h)
+		i.Strides[p] = stride
+	}
+	return i, true
+}
+
+// SetPostproc sets the postprocessing of a VP8 decoder, which must be created with postproc.
+func (d *Decoder) SetPostproc(flags, deblockingLevel, noiseLevel int) error {
+	return toError(C.libvpx_set_postproc(d.ctx, C.int(flags), C.int(deblockingLevel), C.int(noiseLevel)))
+}
+
+// SetSkipLoopFilter sets whether a VP9 decoder skips the loop filter, which saves decoding time at the cost of blocking artifacts.
+func (d *Decoder) SetSkipLoopFilter(skip bool) error {
+	var v C.int
+	if skip {
+		v = 1
+	}
+	return toError(C.libvpx_set_skip_loop_filter(d.ctx, v))
+}
@@ -0,0 +1,403 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+//go:build ignore
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hajimehoshi/webmplayer/internal/cgen"
+)
+
+func main() {
+	if err := xmain(); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+const version = "1.14.1"
+
+func xmain() error {
+	// Only the decoders are built for a generic target without SIMD, as libvpx's SIMD code needs an assembler.
+	op := &cgen.GenerateOptions{
+		ProjectName: "libvpx",
+		TarGzURL:    "https://github.com/webmproject/libvpx/archive/refs/tags/v" + version + ".tar.gz",
+		AllowedFiles: []string{
+			"AUTHORS",
+			"LICENSE",
+			"PATENTS",
+		},
+		BlockedFiles: []string{
+			// Tools.
+			"args.c",
+			"args.h",
+			"ivfdec.c",
+			"ivfdec.h",
+			"ivfenc.c",
+			"ivfenc.h",
+			"md5_utils.c",
+			"md5_utils.h",
+			"rate_hist.c",
+			"rate_hist.h",
+			"tools_common.c",
+			"tools_common.h",
+			"video_common.h",
+			"video_reader.c",
+			"video_reader.h",
+			"video_writer.c",
+			"video_writer.h",
+			"vpxdec.c",
+			"vpxenc.c",
+			"vpxenc.h",
+			"vpxstats.c",
+			"vpxstats.h",
+			"warnings.c",
+			"warnings.h",
+			"webmdec.h",
+			"webmenc.h",
+			"y4menc.c",
+			"y4menc.h",
+			"y4minput.c",
+			"y4minput.h",
+
+			// Encoders.
+			"vp8/common/vp8_skin_detection.c",
+			"vp8/vp8_cx_iface.c",
+			"vp9/vp9_cx_iface.c",
+			"vpx_dsp/avg.c",
+			"vpx_dsp/bitwriter.c",
+			"vpx_dsp/bitwriter_buffer.c",
+			"vpx_dsp/fastssim.c",
+			"vpx_dsp/fwd_txfm.c",
+			"vpx_dsp/psnr.c",
+			"vpx_dsp/psnrhvs.c",
+			"vpx_dsp/quantize.c",
+			"vpx_dsp/sad.c",
+			"vpx_dsp/sse.c",
+			"vpx_dsp/ssim.c",
+			"vpx_dsp/subtract.c",
+			"vpx_dsp/sum_squares.c",
+
+			// Disabled features.
+			"vp8/common/context.c",
+			"vp8/common/debugmodes.c",
+			"vp8/decoder/error_concealment.c",
+			"vp9/common/vp9_debugmodes.c",
+			"vp9/common/vp9_mfqe.c",
+			"vp9/common/vp9_postproc.c",
+			"vpx_util/vpx_debug_util.c",
+
+			// CPU detection and SIMD.
+			"vpx_ports/aarch32_cpudetect.c",
+			"vpx_ports/aarch64_cpudetect.c",
+			"vpx_ports/emms_mmx.c",
+			"vpx_ports/loongarch_cpudetect.c",
+			"vpx_ports/mips_cpudetect.c",
+			"vpx_ports/ppc_cpudetect.c",
+		},
+		BlockedDirs: []string{
+			"examples",
+			"test",
+			"third_party",
+			"tools",
+			"vp8/common/arm",
+			"vp8/common/loongarch",
+			"vp8/common/mips",
+			"vp8/common/x86",
+			"vp8/encoder",
+			"vp9/common/arm",
+			"vp9/common/mips",
+			"vp9/common/ppc",
+			"vp9/common/x86",
+			"vp9/encoder",
+			"vpx_dsp/arm",
+			"vpx_dsp/loongarch",
+			"vpx_dsp/mips",
+			"vpx_dsp/ppc",
+			"vpx_dsp/x86",
+			"vpx_scale/mips",
+		},
+	}
+	if err := cgen.Generate(op); err != nil {
+		return err
+	}
+
+	// Generate the files that libvpx's configure script generates.
+	if err := os.WriteFile("vpx_config.h", []byte(vpxConfigH), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile("vpx_config.c", []byte(vpxConfigC), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile("vpx_version.h", []byte(vpxVersionH()), 0644); err != nil {
+		return err
+	}
+	if err := generateRTCDHeaders("v" + version + ".tar.gz"); err != nil {
+		return err
+	}
+	return nil
+}
+
+var includeRe = regexp.MustCompile(`(?m)^#include "[^"]+"$`)
+
+// generateRTCDHeaders generates the headers of the run-time CPU detection (RTCD) by libvpx's rtcd.pl.
+// Without SIMD, the headers just declare the C functions.
+func generateRTCDHeaders(tarGzFileName string) error {
+	tmp, err := os.MkdirTemp("", "libvpx-rtcd-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	rtcds := map[string]string{
+		"vp8_rtcd":       "vp8/common/rtcd_defs.pl",
+		"vp9_rtcd":       "vp9/common/vp9_rtcd_defs.pl",
+		"vpx_dsp_rtcd":   "vpx_dsp/vpx_dsp_rtcd_defs.pl",
+		"vpx_scale_rtcd": "vpx_scale/vpx_scale_rtcd.pl",
+	}
+	files := []string{"build/make/rtcd.pl"}
+	for _, defs := range rtcds {
+		files = append(files, defs)
+	}
+	if err := extractFiles(tmp, tarGzFileName, files); err != nil {
+		return err
+	}
+
+	// rtcd.pl reads the enabled features from a config file in the format of libvpx's Makefiles.
+	var config bytes.Buffer
+	for _, m := range regexp.MustCompile(`(?m)^#define ((?:CONFIG|HAVE)_\w+) 1$`).FindAllStringSubmatch(vpxConfigH, -1) {
+		fmt.Fprintf(&config, "%s=yes\n", m[1])
+	}
+	configFileName := filepath.Join(tmp, "config.mk")
+	if err := os.WriteFile(configFileName, config.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	for sym, defs := range rtcds {
+		var out bytes.Buffer
+		cmd := exec.Command("perl", filepath.Join(tmp, "build/make/rtcd.pl"), "--arch=generic", "--sym="+sym, "--config="+configFileName, filepath.Join(tmp, defs))
+		cmd.Stdout = &out
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return err
+		}
+		// Flatten the included paths like cgen does.
+		h := includeRe.ReplaceAllFunc(out.Bytes(), func(line []byte) []byte {
+			return bytes.ReplaceAll(line, []byte("/"), []byte("_"))
+		})
+		if err := os.WriteFile(sym+".h", h, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractFiles extracts the given files in the tar.gz file into dir.
+func extractFiles(dir string, tarGzFileName string, names []string) error {
+	f, err := os.Open(tarGzFileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	r := tar.NewReader(s)
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		// Remove the top directory like the entries of cgen.
+		_, name, ok := strings.Cut(header.Name, "/")
+		if !ok {
+			continue
+		}
+		for _, n := range names {
+			if name != n {
+				continue
+			}
+			bs, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			p := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(p, bs, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func vpxVersionH() string {
+	var major, minor, patch int
+	if _, err := fmt.Sscanf(version, "%d.%d.%d", &major, &minor, &patch); err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf(`// This file is generated. Do not edit.
+#define VERSION_MAJOR  %[1]d
+#define VERSION_MINOR  %[2]d
+#define VERSION_PATCH  %[3]d
+#define VERSION_EXTRA  ""
+#define VERSION_PACKED ((VERSION_MAJOR<<16)|(VERSION_MINOR<<8)|(VERSION_PATCH))
+#define VERSION_STRING_NOSP "v%[1]d.%[2]d.%[3]d"
+#define VERSION_STRING      " v%[1]d.%[2]d.%[3]d"
+`, major, minor, patch)
+}
+
+// vpxConfigH is vpx_config.h generated by the configure script with vpxConfigOptions,
+// except for the platform-dependent values, which are determined by the preprocessor.
+const vpxConfigH = `/* Copyright (c) 2011 The WebM project authors. All Rights Reserved. */
+/*  */
+/* Use of this source code is governed by a BSD-style license */
+/* that can be found in the LICENSE file in the root of the source */
+/* tree. An additional intellectual property rights grant can be found */
+/* in the file PATENTS.  All contributing project authors may */
+/* be found in the AUTHORS file in the root of the source tree. */
+/* This file automatically generated by configure. Do not edit! */
+#ifndef VPX_CONFIG_H
+#define VPX_CONFIG_H
+#define RESTRICT
+#define INLINE      inline
+#define VPX_ARCH_ARM 0
+#define VPX_ARCH_AARCH64 0
+#define VPX_ARCH_MIPS 0
+#define VPX_ARCH_X86 0
+#define VPX_ARCH_X86_64 0
+#define VPX_ARCH_PPC 0
+#define VPX_ARCH_LOONGARCH 0
+#define HAVE_NEON_ASM 0
+#define HAVE_NEON 0
+#define HAVE_NEON_DOTPROD 0
+#define HAVE_NEON_I8MM 0
+#define HAVE_SVE 0
+#define HAVE_MIPS32 0
+#define HAVE_DSPR2 0
+#define HAVE_MSA 0
+#define HAVE_MIPS64 0
+#define HAVE_MMX 0
+#define HAVE_SSE 0
+#define HAVE_SSE2 0
+#define HAVE_SSE3 0
+#define HAVE_SSSE3 0
+#define HAVE_SSE4_1 0
+#define HAVE_AVX 0
+#define HAVE_AVX2 0
+#define HAVE_AVX512 0
+#define HAVE_VSX 0
+#define HAVE_MMI 0
+#define HAVE_LSX 0
+#define HAVE_LASX 0
+#define HAVE_VPX_PORTS 1
+#if defined(_WIN32)
+#define HAVE_PTHREAD_H 0
+#define HAVE_UNISTD_H 0
+#else
+#define HAVE_PTHREAD_H 1
+#define HAVE_UNISTD_H 1
+#endif
+#define CONFIG_DEPENDENCY_TRACKING 1
+#define CONFIG_EXTERNAL_BUILD 0
+#define CONFIG_INSTALL_DOCS 0
+#define CONFIG_INSTALL_BINS 1
+#define CONFIG_INSTALL_LIBS 1
+#define CONFIG_INSTALL_SRCS 0
+#define CONFIG_DEBUG 0
+#define CONFIG_GPROF 0
+#define CONFIG_GCOV 0
+#define CONFIG_RVCT 0
+#define CONFIG_GCC 1
+#define CONFIG_MSVS 0
+#define CONFIG_PIC 1
+#if defined(__BYTE_ORDER__) && __BYTE_ORDER__ == __ORDER_BIG_ENDIAN__
+#define CONFIG_BIG_ENDIAN 1
+#else
+#define CONFIG_BIG_ENDIAN 0
+#endif
+#define CONFIG_CODEC_SRCS 0
+#define CONFIG_DEBUG_LIBS 0
+#define CONFIG_DEQUANT_TOKENS 0
+#define CONFIG_DC_RECON 0
+#define CONFIG_RUNTIME_CPU_DETECT 0
+#define CONFIG_POSTPROC 1
+#define CONFIG_VP9_POSTPROC 0
+#define CONFIG_MULTITHREAD 1
+#define CONFIG_INTERNAL_STATS 0
+#define CONFIG_VP8_ENCODER 0
+#define CONFIG_VP8_DECODER 1
+#define CONFIG_VP9_ENCODER 0
+#define CONFIG_VP9_DECODER 1
+#define CONFIG_VP8 1
+#define CONFIG_VP9 1
+#define CONFIG_ENCODERS 0
+#define CONFIG_DECODERS 1
+#define CONFIG_STATIC_MSVCRT 0
+#define CONFIG_SPATIAL_RESAMPLING 1
+#define CONFIG_REALTIME_ONLY 0
+#define CONFIG_ONTHEFLY_BITPACKING 0
+#define CONFIG_ERROR_CONCEALMENT 0
+#define CONFIG_SHARED 0
+#define CONFIG_STATIC 1
+#define CONFIG_SMALL 0
+#define CONFIG_POSTPROC_VISUALIZER 0
+#define CONFIG_OS_SUPPORT 1
+#define CONFIG_UNIT_TESTS 0
+#define CONFIG_WEBM_IO 0
+#define CONFIG_LIBYUV 0
+#define CONFIG_DECODE_PERF_TESTS 0
+#define CONFIG_ENCODE_PERF_TESTS 0
+#define CONFIG_MULTI_RES_ENCODING 0
+#define CONFIG_TEMPORAL_DENOISING 1
+#define CONFIG_VP9_TEMPORAL_DENOISING 0
+#define CONFIG_COEFFICIENT_RANGE_CHECKING 0
+#define CONFIG_VP9_HIGHBITDEPTH 1
+#define CONFIG_BETTER_HW_COMPATIBILITY 0
+#define CONFIG_EXPERIMENTAL 0
+#define CONFIG_SIZE_LIMIT 0
+#define CONFIG_ALWAYS_ADJUST_BPM 0
+#define CONFIG_BITSTREAM_DEBUG 0
+#define CONFIG_MISMATCH_DEBUG 0
+#define CONFIG_FP_MB_STATS 0
+#define CONFIG_EMULATE_HARDWARE 0
+#define CONFIG_NON_GREEDY_MV 0
+#define CONFIG_RATE_CTRL 0
+#define CONFIG_COLLECT_COMPONENT_TIMING 0
+#endif /* VPX_CONFIG_H */
+`
+
+// vpxConfigOptions is the options of the configure script for vpxConfigH.
+const vpxConfigOptions = "--target=generic-gnu --disable-vp8-encoder --disable-vp9-encoder --enable-vp9-highbitdepth --enable-postproc --disable-examples --disable-tools --disable-docs --disable-unit-tests --disable-install-docs --disable-webm-io --disable-libyuv --enable-multithread"
+
+var vpxConfigC = `/* Copyright (c) 2011 The WebM project authors. All Rights Reserved. */
+/*  */
+/* Use of this source code is governed by a BSD-style license */
+/* that can be found in the LICENSE file in the root of the source */
+/* tree. An additional intellectual property rights grant can be found */
+/* in the file PATENTS.  All contributing project authors may */
+/* be found in the AUTHORS file in the root of the source tree. */
+#include "vpx_vpx_codec.h"
+static const char* const cfg = "` + vpxConfigOptions + `";
+const char *vpx_codec_build_config(void) {return cfg;}
+`
@@ -0,0 +1,8 @@
+// This file is generated. Do not edit.
+#define VERSION_MAJOR  1
+#define VERSION_MINOR  14
+#define VERSION_PATCH  1
+#define VERSION_EXTRA  ""
+#define VERSION_PACKED ((VERSION_MAJOR<<16)|(VERSION_MINOR<<8)|(VERSION_PATCH))
+#define VERSION_STRING_NOSP "v1.14.1"
+#define VERSION_STRING      " v1.14.1"
@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+// Package ogg implements a minimal reader of Ogg bitstreams.
+//
+// See RFC 3533 for the format.
+package ogg
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// HeaderTypeContinued indicates that the first packet of the page continues from the previous page.
+	HeaderTypeContinued = 0x01
+
+	// HeaderTypeBOS indicates the first page of a logical bitstream.
+	HeaderTypeBOS = 0x02
+
+	// HeaderTypeEOS indicates the last page of a logical bitstream.
+	HeaderTypeEOS = 0x04
+)
+
+const pageHeaderSize = 27
+
+// Page is a page of an Ogg bitstream.
+type Page struct {
+	// Offset is the position of the page in the file.
+	Offset int64
+
+	// Size is the size of the page including the header.
+	Size int64
+
+	HeaderType byte
+
+	// Granule is the granule position of the page, or -1 if no packet is completed in the page.
+	Granule int64
+
+	Serial   uint32
+	Sequence uint32
+
+	// Segments is the lacing values of the page.
+	Segments []byte
+
+	// Body is the body of the page. Body is nil if the body is skipped.
+	Body []byte
+}
+
+// Packet is a packet of a logical bitstream.
+type Packet struct {
+	Serial uint32
+	Data   []byte
+
+	// Granule is the granule position of the page if the packet is the last packet completed in the page, or -1 otherwise.
+	Granule int64
+
+	// BOS reports whether the packet is in the first page of the logical bitstream.
+	BOS bool
+}
+
+// Reader reads pages and packets of an Ogg bitstream.
+//
+// Reader doesn't verify the CRC checksums of pages.
+type Reader struct {
+	r      io.ReadSeeker
+	offset int64
+
+	// partial is the data of packets continued to the next page for each serial.
+	partial map[uint32][]byte
+
+	packets []Packet
+}
+
+// NewReader creates a reader reading from the current position of r.
+func NewReader(r io.ReadSeeker) (*Reader, error) {
+	offset, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		r:       r,
+		offset:  offset,
+		partial: map[uint32][]byte{},
+	}, nil
+}
+
+// Offset returns the position of the next page.
+func (r *Reader) Offset() int64 {
+	return r.offset
+}
+
+// SeekPage moves the position to the page at the given offset.
+// The buffered packets and the packet data continued from the previous pages are discarded.
+func (r *Reader) SeekPage(offset int64) error {
+	if _, err := r.r.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	r.offset = offset
+	clear(r.partial)
+	r.packets = r.packets[:0]
+	return nil
+}
+
+// NextPage reads the next page.
+// If skipBody is true, the page body is skipped without reading.
+//
+// NextPage returns io.EOF at the end of the bitstream.
+func (r *Reader) NextPage(skipBody bool) (*Page, error) {
+	var header [pageHeaderSize]byte
+	if _, err := io.ReadFull(r.r, header[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			// A truncated page at the end is ignored.
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	if string(header[:4]) != "OggS" {
+		return nil, fmt.Errorf("ogg: invalid capture pattern at %d", r.offset)
+	}
+	if header[4] != 0 {
+		return nil, fmt.Errorf("ogg: unsupported version: %d", header[4])
+	}
+
+	p := &Page{
+		Offset:     r.offset,
+		HeaderType: header[5],
+		Granule:    int64(binary.LittleEndian.Uint64(header[6:14])),
+		Serial:     binary.LittleEndian.Uint32(header[14:18]),
+		Sequence:   binary.LittleEndian.Uint32(header[18:22]),
+		Segments:   make([]byte, header[26]),
+	}
+	if _, err := io.ReadFull(r.r, p.Segments); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	var bodySize int
+	for _, s := range p.Segments {
+		bodySize += int(s)
+	}
+	if skipBody {
+		if _, err := r.r.Seek(int64(bodySize), io.SeekCurrent); err != nil {
+			return nil, err
+		}
+	} else {
+		p.Body = make([]byte, bodySize)
+		if _, err := io.ReadFull(r.r, p.Body); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+	}
+
+	p.Size = int64(pageHeaderSize + len(p.Segments) + bodySize)
+	r.offset += p.Size
+	return p, nil
+}
+
+// NextPacket reads the next packet of any logical bitstream.
+//
+// NextPacket returns io.EOF at the end of the bitstream.
+func (r *Reader) NextPacket() (Packet, error) {
+	for len(r.packets) == 0 {
+		page, err := r.NextPage(false)
+		if err != nil {
+			return Packet{}, err
+		}
+		r.appendPackets(page)
+	}
+	p := r.packets[0]
+	r.packets = r.packets[1:]
+	return p, nil
+}
+
+func (r *Reader) appendPackets(page *Page) {
+	data := r.partial[page.Serial]
+	delete(r.partial, page.Serial)
+	if page.HeaderType&HeaderTypeContinued == 0 {
+		// The previous packet was not completed, which can happen after a lost page.
+		data = nil
+	}
+	// skip reports whether the current packet is continued from a page not read, which happens after seeking.
+	skip := page.HeaderType&HeaderTypeContinued != 0 && data == nil
+
+	first := len(r.packets)
+	var body int
+	for _, s := range page.Segments {
+		if !skip {
+			data = append(data, page.Body[body:body+int(s)]...)
+		}
+		body += int(s)
+		if s == 255 {
+			continue
+		}
+		if !skip {
+			r.packets = append(r.packets, Packet{
+				Serial:  page.Serial,
+				Data:    data,
+				Granule: -1,
+				BOS:     page.HeaderType&HeaderTypeBOS != 0,
+			})
+		}
+		data = nil
+		skip = false
+	}
+	if len(page.Segments) > 0 && page.Segments[len(page.Segments)-1] == 255 && !skip {
+		r.partial[page.Serial] = data
+	}
+
+	if len(r.packets) > first {
+		r.packets[len(r.packets)-1].Granule = page.Granule
+	}
+}
+
+// SeekAfterPage moves the position to the page at the given offset, and discards the packets completed in the page.
+// Then, the next packet of the page's logical bitstream is the one starting at the granule position of the page.
+func (r *Reader) SeekAfterPage(offset int64) error {
+	if err := r.SeekPage(offset); err != nil {
+		return err
+	}
+	page, err := r.NextPage(false)
+	if err != nil {
+		return err
+	}
+	r.appendPackets(page)
+	r.packets = r.packets[:0]
+	return nil
+}
@@ -0,0 +1,7 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+// Package webm is a fork of github.com/ebml-go/webm.
+//
+// The fork reads more elements, such as BlockAdditions, which the original package discards.
+package webm
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webm
+
+import (
+	"context"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/ebml-go/ebml"
+)
+
+// keyframe is a keyframe that is the first block of the indexed track in its cluster, from which decoding can start after seeking to the cluster.
+type keyframe struct {
+	timecode time.Duration
+	cluster  time.Duration
+}
+
+// blockTimecode returns the timecode of a block in a cluster with the given timecode, in the same way as sendBlock.
+func blockTimecode(data []byte, tbase time.Duration) time.Duration {
+	return tbase + time.Millisecond*time.Duration(uint(data[1])<<8+uint(data[2]))
+}
+
+// isIndexTrackBlock reports whether the block belongs to the track to index keyframes.
+func (r *Reader) isIndexTrackBlock(data []byte) bool {
+	return r.indexTrack != 0 && uint(data[0])&0x7f == r.indexTrack
+}
+
+func (r *Reader) addKeyframe(k keyframe) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	i := sort.Search(len(r.keyframes), func(i int) bool {
+		return r.keyframes[i].timecode >= k.timecode
+	})
+	if i < len(r.keyframes) && r.keyframes[i].timecode == k.timecode {
+		return
+	}
+	r.keyframes = append(r.keyframes, keyframe{})
+	copy(r.keyframes[i+1:], r.keyframes[i:])
+	r.keyframes[i] = k
+}
+
+// KeyframeBefore returns the timecode of the last known keyframe of the video track, or the audio track if there is no video, at or before t, and the timecode of its cluster to seek.
+// KeyframeBefore returns false if there is no such keyframe.
+//
+// A keyframe is known after its cluster is read, or after BuildIndex.
+// Only keyframes that are the first blocks of the track in their clusters are indexed.
+func (r *Reader) KeyframeBefore(t time.Duration) (timecode, cluster time.Duration, ok bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	i := sort.Search(len(r.keyframes), func(i int) bool {
+		return r.keyframes[i].timecode > t
+	})
+	if i == 0 {
+		return 0, 0, false
+	}
+	k := r.keyframes[i-1]
+	return k.timecode, k.cluster, true
+}
+
+// IsIndexed reports whether BuildIndex has been completed.
+func (r *Reader) IsIndexed() bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.indexed
+}
+
+// BuildIndex scans all the clusters to index their positions and the keyframes.
+//
+// rs must have the same content as the reader's source at the same offsets, and must not be used by the reader.
+// BuildIndex can be called concurrently with reading.
+func (r *Reader) BuildIndex(ctx context.Context, rs io.ReadSeeker) error {
+	var m WebM
+	clusters, _, err := parseHeaders(rs, &m)
+	if err != nil {
+		return err
+	}
+
+	for clusters != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		e, err := clusters.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if e.Id != 0x1f43b675 {
+			if _, err := e.Seek(e.Size(), io.SeekCurrent); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var c Cluster
+		if err := e.Unmarshal(&c); err == nil {
+			// A cluster without blocks.
+			continue
+		} else if err.Error() != "Reached payload" {
+			return err
+		}
+		tbase := time.Millisecond * time.Duration(c.Timecode)
+		r.m.Lock()
+		r.index.append(seekEntry{tbase, e.Offset})
+		r.m.Unlock()
+
+		if err := r.scanCluster(e, tbase); err != nil && err != io.EOF {
+			return err
+		}
+		// Skip the rest of the cluster.
+		if _, err := e.Seek(e.Size(), io.SeekCurrent); err != nil {
+			return err
+		}
+	}
+
+	r.m.Lock()
+	r.indexed = true
+	r.m.Unlock()
+	return nil
+}
+
+// scanCluster indexes the first block of the indexed track in the cluster if it is a keyframe.
+func (r *Reader) scanCluster(cluster *ebml.Element, tbase time.Duration) error {
+	for {
+		e, err := cluster.Next()
+		if err != nil {
+			return err
+		}
+
+		var blk []byte
+		var key bool
+		switch e.Id {
+		case 0xa3:
+			var header [4]byte
+			if _, err := io.ReadFull(e, header[:]); err != nil {
+				return err
+			}
+			blk = header[:]
+			key = header[3]&0x80 != 0
+		case 0xa0:
+			var bg BlockGroup
+			if err := e.Unmarshal(&bg); err != nil && err != io.EOF {
+				return err
+			}
+			blk = bg.Block
+			key = bg.ReferenceBlock == 0
+		}
+		if _, err := e.Seek(e.Size(), io.SeekCurrent); err != nil {
+			return err
+		}
+
+		if len(blk) < 4 || !r.isIndexTrackBlock(blk) {
+			continue
+		}
+		if key {
+			r.addKeyframe(keyframe{blockTimecode(blk, tbase), tbase})
+		}
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webm
+
+import (
+	"io"
+	"math/bits"
+)
+
+// hasUnknownSize reports whether the element at offset in r has an unknown size,
+// which live streams use for the segment and the clusters as their ends are not known when they are written.
+//
+// The position of r is restored after reading the element's header.
+func hasUnknownSize(r io.ReadSeeker, offset int64) (bool, error) {
+	curr, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false, err
+	}
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return false, err
+	}
+	unknown, err := readUnknownSize(r)
+	if _, err := r.Seek(curr, io.SeekStart); err != nil {
+		return false, err
+	}
+	return unknown, err
+}
+
+// readUnknownSize reads the header of an element, and reports whether all the bits of its size are 1.
+func readUnknownSize(r io.Reader) (bool, error) {
+	var buf [8]byte
+
+	// Skip the ID.
+	if _, err := io.ReadFull(r, buf[:1]); err != nil {
+		return false, err
+	}
+	idLen := bits.LeadingZeros8(buf[0]) + 1
+	if idLen > 4 {
+		return false, nil
+	}
+	if _, err := io.ReadFull(r, buf[:idLen-1]); err != nil {
+		return false, err
+	}
+
+	if _, err := io.ReadFull(r, buf[:1]); err != nil {
+		return false, err
+	}
+	sizeLen := bits.LeadingZeros8(buf[0]) + 1
+	if sizeLen > 8 {
+		return false, nil
+	}
+	if _, err := io.ReadFull(r, buf[1:sizeLen]); err != nil {
+		return false, err
+	}
+	// The bits after the length marker.
+	mask := byte(0xff) >> sizeLen
+	if buf[0]&mask != mask {
+		return false, nil
+	}
+	for _, b := range buf[1:sizeLen] {
+		if b != 0xff {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// isTopLevelID reports whether id is the ID of a top-level element in a segment.
+// Such an element ends a cluster of an unknown size.
+func isTopLevelID(id uint) bool {
+	switch id {
+	case 0x1f43b675, // Cluster
+		0x1c53bb6b, // Cues
+		0x1254c367, // Tags
+		0x114d9b74, // SeekHead
+		0x1549a966, // Info
+		0x1654ae6b, // Tracks
+		0x1043a770, // Chapters
+		0x1941a469, // Attachments
+		0x1a45dfa3: // EBML, the header of a next segment
+		return true
+	}
+	return false
+}
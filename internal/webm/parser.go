@@ -0,0 +1,388 @@
+// Copyright 2011 The ebml-go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webm
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/ebml-go/ebml"
+)
+
+type TrackType uint8 // ffmpeg define this in 8 bits(uint8 in Go)
+
+const (
+	TrackTypeNone     TrackType = 0x0
+	TrackTypeVideo    TrackType = 0x1
+	TrackTypeAudio    TrackType = 0x2
+	TrackTypeComplex  TrackType = 0x3
+	TrackTypeLogo     TrackType = 0x10
+	TrackTypeSubtitle TrackType = 0x11
+	TrackTypeButtons  TrackType = 0x12
+	TrackTypeControl  TrackType = 0x20
+	TrackTypeMetadata TrackType = 0x21
+)
+
+type WebM struct {
+	Header  `ebml:"1a45dfa3"`
+	Segment `ebml:"18538067"`
+}
+
+func (w *WebM) FindFirstVideoTrack() *TrackEntry {
+	t := w.Segment.Tracks.TrackEntry
+	for i, l := 0, len(t); i < l; i++ {
+		if t[i].IsVideo() {
+			return &t[i]
+		}
+	}
+	return nil
+}
+
+func (w *WebM) FindFirstAudioTrack() *TrackEntry {
+	t := w.Segment.Tracks.TrackEntry
+	for i, l := 0, len(t); i < l; i++ {
+		if t[i].IsAudio() {
+			return &t[i]
+		}
+	}
+	return nil
+}
+
+type Header struct {
+	EBMLVersion        uint   `ebml:"4286" ebmldef:"1"`
+	EBMLReadVersion    uint   `ebml:"42f7" ebmldef:"1"`
+	EBMLMaxIDLength    uint   `ebml:"42f2" ebmldef:"4"`
+	EBMLMaxSizeLength  uint   `ebml:"42f3" ebmldef:"8"`
+	DocType            string `ebml:"4282"`
+	DocTypeVersion     uint   `ebml:"4287" ebmldef:"1"`
+	DocTypeReadVersion uint   `ebml:"4285" ebmldef:"1"`
+}
+
+type Segment struct {
+	cluster            []Cluster `ebml:"1F43B675" ebmlstop:"1"`
+	SeekHead           `ebml:"114D9B74" ebmlstop:"1"`
+	SegmentInformation `ebml:"1549A966"`
+	Tracks             `ebml:"1654AE6B"`
+	Cues               `ebml:"1C53BB6B"`
+	Tags               `ebml:"1254C367"`
+}
+
+type Tracks struct {
+	TrackEntry []TrackEntry `ebml:"AE"`
+}
+
+type TrackEntry struct {
+	TrackNumber     uint   `ebml:"D7"`
+	TrackUID        uint64 `ebml:"73C5"`
+	TrackType       uint   `ebml:"83"`
+	FlagEnabled     uint   `ebml:"B9" ebmldef:"1"`
+	FlagDefault     uint   `ebml:"88" ebmldef:"1"`
+	FlagForced      uint   `ebml:"55AA" ebmldef:"0"`
+	FlagLacing      uint   `ebml:"9C" ebmldef:"1"`
+	DefaultDuration uint64 `ebml:"23E383"`
+	Name            string `ebml:"536E"`
+	Language        string `ebml:"22B59C" ebmldef:"eng"`
+	CodecID         string `ebml:"86"`
+	CodecPrivate    []byte `ebml:"63A2"`
+	CodecName       string `ebml:"258688"`
+	CodecDelay      uint64 `ebml:"56AA"`
+	Video           `ebml:"E0"`
+	Audio           `ebml:"E1"`
+}
+
+func (t *TrackEntry) GetDefaultDuration() time.Duration {
+	return time.Duration(t.DefaultDuration)
+}
+
+func (t *TrackEntry) IsVideo() bool {
+	return TrackType(t.TrackType) == TrackTypeVideo
+}
+
+func (t *TrackEntry) IsAudio() bool {
+	return TrackType(t.TrackType) == TrackTypeAudio
+}
+
+func (t *TrackEntry) IsSubtitle() bool {
+	return TrackType(t.TrackType) == TrackTypeSubtitle
+}
+
+type Video struct {
+	FlagInterlaced  uint `ebml:"9A" ebmldef:"0"`
+	StereoMode      uint `ebml:"53B8" ebmldef:"0"`
+	PixelWidth      uint `ebml:"B0"`
+	PixelHeight     uint `ebml:"BA"`
+	PixelCropBottom uint `ebml:"54AA" ebmldef:"0"`
+	PixelCropTop    uint `ebml:"54BB" ebmldef:"0"`
+	PixelCropLeft   uint `ebml:"54CC" ebmldef:"0"`
+	PixelCropRight  uint `ebml:"54DD" ebmldef:"0"`
+	DisplayWidth    uint `ebml:"54B0" ebmldeflink:"PixelWidth"`
+	DisplayHeight   uint `ebml:"54BA" ebmldeflink:"PixelHeight"`
+	DisplayUnit     uint `ebml:"54B2" ebmldef:"0"`
+	AspectRatioType uint `ebml:"54B3" ebmldef:"0"`
+	AlphaMode       uint `ebml:"53C0" ebmldef:"0"`
+	Colour          `ebml:"55B0"`
+	Projection      `ebml:"7670"`
+}
+
+// Projection describes the projection of a video track.
+// All the fields are zero if the track has no Projection element.
+// The poses are in degrees, and ProjectionPoseRoll is a counter-clockwise rotation.
+type Projection struct {
+	ProjectionType      uint    `ebml:"7671" ebmldef:"0"`
+	ProjectionPrivate   []byte  `ebml:"7672"`
+	ProjectionPoseYaw   float64 `ebml:"7673"`
+	ProjectionPosePitch float64 `ebml:"7674"`
+	ProjectionPoseRoll  float64 `ebml:"7675"`
+}
+
+// Colour describes the colour format of a video track.
+// All the fields are zero if the track has no Colour element.
+type Colour struct {
+	MatrixCoefficients      uint `ebml:"55B1" ebmldef:"2"`
+	BitsPerChannel          uint `ebml:"55B2" ebmldef:"0"`
+	ChromaSubsamplingHorz   uint `ebml:"55B3"`
+	ChromaSubsamplingVert   uint `ebml:"55B4"`
+	CbSubsamplingHorz       uint `ebml:"55B5"`
+	CbSubsamplingVert       uint `ebml:"55B6"`
+	ChromaSitingHorz        uint `ebml:"55B7" ebmldef:"0"`
+	ChromaSitingVert        uint `ebml:"55B8" ebmldef:"0"`
+	Range                   uint `ebml:"55B9" ebmldef:"0"`
+	TransferCharacteristics uint `ebml:"55BA" ebmldef:"2"`
+	Primaries               uint `ebml:"55BB" ebmldef:"2"`
+	MaxCLL                  uint `ebml:"55BC"`
+	MaxFALL                 uint `ebml:"55BD"`
+	MasteringMetadata       `ebml:"55D0"`
+}
+
+// MasteringMetadata describes the mastering display of HDR content.
+type MasteringMetadata struct {
+	PrimaryRChromaticityX   float64 `ebml:"55D1"`
+	PrimaryRChromaticityY   float64 `ebml:"55D2"`
+	PrimaryGChromaticityX   float64 `ebml:"55D3"`
+	PrimaryGChromaticityY   float64 `ebml:"55D4"`
+	PrimaryBChromaticityX   float64 `ebml:"55D5"`
+	PrimaryBChromaticityY   float64 `ebml:"55D6"`
+	WhitePointChromaticityX float64 `ebml:"55D7"`
+	WhitePointChromaticityY float64 `ebml:"55D8"`
+	LuminanceMax            float64 `ebml:"55D9"`
+	LuminanceMin            float64 `ebml:"55DA"`
+}
+
+type Audio struct {
+	SamplingFrequency       float64 `ebml:"B5" ebmldef:"8000.0"`
+	OutputSamplingFrequency float64 `ebml:"78B5" ebmldeflink:"SamplingFrequency"`
+	Channels                uint    `ebml:"9F" ebmldef:"1"`
+	BitDepth                uint    `ebml:"6264"`
+}
+
+type SeekHead struct {
+	Seek []Seek `ebml:"4DBB"`
+}
+
+type Seek struct {
+	SeekID       []byte `ebml:"53AB"`
+	SeekPosition int64  `ebml:"53AC"`
+}
+
+type SegmentInformation struct {
+	TimecodeScale uint    `ebml:"2AD7B1" ebmldef:"1000000"`
+	Duration      float64 `ebml:"4489"`
+	DateUTC       []byte  `ebml:"4461"`
+	MuxingApp     string  `ebml:"4D80"`
+	WritingApp    string  `ebml:"5741"`
+}
+
+// return duration in seconds
+func (s *SegmentInformation) GetDuration() time.Duration {
+	return time.Second * time.Duration(
+		s.Duration*float64(s.TimecodeScale)/1000000000)
+}
+
+// return duration in milliseconds
+func (s *SegmentInformation) GetDurationMs() time.Duration {
+	return time.Millisecond * time.Duration(
+		s.Duration*float64(s.TimecodeScale)/1000000)
+}
+
+type Cluster struct {
+	simpleBlock []byte     `ebml:"A3" ebmlstop:"1"`
+	Timecode    uint       `ebml:"E7"`
+	PrevSize    uint       `ebml:"AB"`
+	Position    uint       `ebml:"A7"`
+	BlockGroup  BlockGroup `ebml:"A0" ebmlstop:"1"`
+}
+
+type BlockGroup struct {
+	Block          []byte   `ebml:"A1"`
+	BlockDuration  uint     `ebml:"9B"`
+	ReferenceBlock int      `ebml:"FB"`
+	DiscardPadding int64    `ebml:"75A2"`
+	CodecState     []byte   `ebml:"A4"`
+	Slices         []Slices `ebml:"8E"`
+	BlockAdditions `ebml:"75A1"`
+}
+
+type BlockAdditions struct {
+	BlockMore []BlockMore `ebml:"A6"`
+}
+
+type BlockMore struct {
+	BlockAddID      uint   `ebml:"EE" ebmldef:"1"`
+	BlockAdditional []byte `ebml:"A5"`
+}
+
+type Slices struct {
+	TimeSlice []TimeSlice `ebml:"E8"`
+}
+
+type TimeSlice struct {
+	LaceNumber uint `ebml:"CC" ebmldef:"0"`
+}
+
+type Cues struct {
+	CuePoint []CuePoint `ebml:"BB"`
+}
+
+type Tags struct {
+	Tag []Tag `ebml:"7373"`
+}
+
+type Tag struct {
+	Targets   `ebml:"63C0"`
+	SimpleTag []SimpleTag `ebml:"67C8"`
+}
+
+type Targets struct {
+	TargetTypeValue uint   `ebml:"68CA" ebmldef:"50"`
+	TargetType      string `ebml:"63CA"`
+	// TagTrackUID is the first TagTrackUID. 0 means all the tracks.
+	TagTrackUID uint64 `ebml:"63C5"`
+}
+
+type SimpleTag struct {
+	TagName     string `ebml:"45A3"`
+	TagLanguage string `ebml:"447A" ebmldef:"und"`
+	TagDefault  uint   `ebml:"4484" ebmldef:"1"`
+	TagString   string `ebml:"4487"`
+	TagBinary   []byte `ebml:"4485"`
+}
+
+// FindTag returns the value of the first SimpleTag with the given name for the given track UID.
+// Tags for all the tracks are also searched.
+func (w *WebM) FindTag(trackUID uint64, name string) (string, bool) {
+	for _, t := range w.Segment.Tags.Tag {
+		if t.TagTrackUID != 0 && t.TagTrackUID != trackUID {
+			continue
+		}
+		for _, st := range t.SimpleTag {
+			if st.TagName == name {
+				return st.TagString, true
+			}
+		}
+	}
+	return "", false
+}
+
+type CuePoint struct {
+	CueTime           int64               `ebml:"B3"`
+	CueTrackPositions []CueTrackPositions `ebml:"B7"`
+}
+
+type CueTrackPositions struct {
+	CueTrack           uint  `ebml:"F7"`
+	CueClusterPosition int64 `ebml:"F1"`
+	CueBlockNumber     uint  `ebml:"5378" ebmldef:"1"`
+}
+
+func Parse(r io.ReadSeeker, m *WebM) (wr *Reader, err error) {
+	clusters, offset, err := parseHeaders(r, m)
+	if err != nil {
+		return nil, err
+	}
+	var indexTrack uint
+	if t := m.FindFirstVideoTrack(); t != nil {
+		indexTrack = t.TrackNumber
+	} else if t := m.FindFirstAudioTrack(); t != nil {
+		indexTrack = t.TrackNumber
+	}
+	// A live stream has an unknown size, and its clusters might have unknown sizes too.
+	var live bool
+	if clusters != nil {
+		live, err = hasUnknownSize(r, clusters.Offset)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newReader(clusters, m.Segment.Cues.CuePoint, offset, indexTrack, live), nil
+}
+
+// parseHeaders parses the elements before the clusters into m,
+// and returns the segment element positioned at the first cluster and the offset of the segment's data.
+func parseHeaders(r io.ReadSeeker, m *WebM) (clusters *ebml.Element, offset int64, err error) {
+	var e *ebml.Element
+	e, err = ebml.RootElement(r)
+	if err == nil {
+		err = e.Unmarshal(m)
+		dt := m.Header.DocType
+		if dt != "webm" && dt != "webm\000" && dt != "matroska" {
+			err = errors.New("Not a WebM or matroska file")
+		}
+		if err != nil && err.Error() == "Reached payload" {
+			segment := err.(ebml.ReachedPayloadError).Element
+			sh, _ := segment.Next()
+			if sh != nil && sh.Id != 0x114d9b74 {
+				// There is no SeekHead before the first cluster, e.g. in a live stream.
+				segment.Seek(sh.Offset, io.SeekStart)
+			} else {
+				sh.Unmarshal(&m.SeekHead)
+			}
+			pos := m.cuesPosition()
+			if pos > 0 {
+				curr, _ := segment.Seek(0, 1)
+				segment.Seek(pos+sh.Offset, 0)
+				ce, _ := segment.Next()
+				ce.Unmarshal(&m.Segment.Cues)
+				segment.Seek(curr, 0)
+			}
+			// Tags are usually placed after the clusters.
+			if pos := m.tagsPosition(); pos > 0 {
+				curr, _ := segment.Seek(0, 1)
+				segment.Seek(pos+sh.Offset, 0)
+				te, err := segment.Next()
+				if err == nil {
+					te.Unmarshal(&m.Segment.Tags)
+				}
+				segment.Seek(curr, 0)
+			}
+			segment.Unmarshal(&m.Segment)
+			clusters = err.(ebml.ReachedPayloadError).Element
+			offset = sh.Offset
+			err = nil
+		}
+	}
+	return
+}
+
+func (m *WebM) tagsPosition() int64 {
+	s := m.Segment.SeekHead.Seek
+	for i, l := 0, len(s); i < l; i++ {
+		if bytes.Equal(s[i].SeekID, []byte{0x12, 0x54, 0xc3, 0x67}) {
+			return s[i].SeekPosition
+		}
+	}
+	return -1
+}
+
+func (m *WebM) cuesPosition() int64 {
+	s := m.Segment.SeekHead.Seek
+	for i, l := 0, len(s); i < l; i++ {
+		if s[i].SeekID[0] == 0x1c {
+			return s[i].SeekPosition
+		}
+	}
+	return -1
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webm
+
+import (
+	"math/bits"
+	"sync"
+
+	"github.com/hajimehoshi/webmplayer/internal/allocstats"
+)
+
+// blockPools is the pools of block buffers, indexed by the logarithms of their capacities.
+var blockPools [48]sync.Pool
+
+// getBlockBuffer returns a buffer with the length n.
+func getBlockBuffer(n int) *[]byte {
+	c := bits.Len(uint(n - 1))
+	if c >= len(blockPools) {
+		allocstats.Add(allocstats.Demux, n)
+		b := make([]byte, n)
+		return &b
+	}
+	if b, ok := blockPools[c].Get().(*[]byte); ok {
+		*b = (*b)[:n]
+		return b
+	}
+	allocstats.Add(allocstats.Demux, 1<<c)
+	b := make([]byte, n, 1<<c)
+	return &b
+}
+
+func putBlockBuffer(b *[]byte) {
+	c := bits.Len(uint(cap(*b) - 1))
+	if c >= len(blockPools) || cap(*b) != 1<<c {
+		return
+	}
+	blockPools[c].Put(b)
+}
+
+// Release returns the packet's data to the reader for reuse, if the data is owned by the packet.
+// Data and Additional must not be used after Release.
+//
+// Release is optional. A packet that is not released is garbage-collected as usual.
+// Release must be called at most once for the packets copied from the same packet.
+func (p *Packet) Release() {
+	if p.buf == nil {
+		return
+	}
+	putBlockBuffer(p.buf)
+	p.buf = nil
+	p.Data = nil
+	p.Additional = nil
+}
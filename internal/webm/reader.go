@@ -0,0 +1,324 @@
+package webm
+
+import (
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ebml-go/ebml"
+
+	"github.com/hajimehoshi/webmplayer/internal/allocstats"
+)
+
+const (
+	BadTC    = time.Duration(-1000000000000000)
+	shutdown = 2 * BadTC
+)
+
+type Packet struct {
+	Data        []byte
+	Timecode    time.Duration
+	TrackNumber uint
+	Invisible   bool
+	Keyframe    bool
+	Discardable bool
+	Rebase      bool
+
+	// Additional is the BlockAdditional data with BlockAddID 1, such as an alpha channel.
+	Additional []byte
+
+	// DiscardPadding is the duration of the samples to discard at the end of the decoded audio packet.
+	DiscardPadding time.Duration
+
+	// buf is the pooled buffer that Data belongs to, if the packet owns it. See Release.
+	buf *[]byte
+}
+
+type Reader struct {
+	Chan   chan Packet
+	seek   chan time.Duration
+	index  seekIndex
+	offset int64
+
+	// indexTrack is the track number to index keyframes: the video track, or the audio track if there is no video.
+	indexTrack uint
+
+	// live indicates whether the segment has an unknown size, like a live stream.
+	// The clusters of a live stream are not indexed, so that the memory usage doesn't grow indefinitely.
+	live bool
+
+	// keyframes is the known keyframes sorted by timecodes. indexed indicates whether BuildIndex has been completed.
+	keyframes []keyframe
+	indexed   bool
+
+	// m protects index, keyframes and indexed, which can be updated by BuildIndex.
+	m sync.Mutex
+}
+
+func (r *Reader) send(p *Packet) {
+	r.Chan <- *p
+}
+
+func remaining(x int8) (rem int) {
+	for x > 0 {
+		rem++
+		x += x
+	}
+	return
+}
+
+func laceSize(v []byte) (val int, rem int) {
+	val = int(v[0])
+	rem = remaining(int8(val))
+	for i, l := 1, rem+1; i < l; i++ {
+		val <<= 8
+		val += int(v[i])
+	}
+	val &= ^(128 << uint(rem*8-rem))
+	return
+}
+
+func laceDelta(v []byte) (val int, rem int) {
+	val, rem = laceSize(v)
+	val -= (1 << (uint(7*(rem+1) - 1))) - 1
+	return
+}
+
+func (r *Reader) sendLaces(p *Packet, d []byte, sz []int) {
+	var curr int
+	for i, l := 0, len(sz); i < l; i++ {
+		if sz[i] != 0 {
+			p.Data = d[curr : curr+sz[i]]
+			r.send(p)
+			curr += sz[i]
+			p.Timecode = BadTC
+		}
+	}
+	p.Data = d[curr:]
+	r.send(p)
+}
+
+func parseXiphSizes(d []byte) (sz []int, curr int) {
+	laces := int(uint(d[4]))
+	sz = make([]int, laces)
+	curr = 5
+	for i := 0; i < laces; i++ {
+		for d[curr] == 255 {
+			sz[i] += 255
+			curr++
+		}
+		sz[i] += int(uint(d[curr]))
+		curr++
+	}
+	return
+}
+
+func parseFixedSizes(d []byte) (sz []int, curr int) {
+	laces := int(uint(d[4]))
+	curr = 5
+	fsz := len(d[curr:]) / (laces + 1)
+	sz = make([]int, laces)
+	for i := 0; i < laces; i++ {
+		sz[i] = fsz
+	}
+	return
+}
+
+func parseEBMLSizes(d []byte) (sz []int, curr int) {
+	laces := int(uint(d[4]))
+	sz = make([]int, laces)
+	curr = 5
+	var rem int
+	sz[0], rem = laceSize(d[curr:])
+	for i := 1; i < laces; i++ {
+		curr += rem + 1
+		var dsz int
+		dsz, rem = laceDelta(d[curr:])
+		sz[i] = sz[i-1] + dsz
+	}
+	curr += rem + 1
+	return
+}
+
+// sendBlock sends the packets in the block.
+// buf is the pooled buffer of data if not nil, which is owned by the packet only when the block is not laced.
+func (r *Reader) sendBlock(data []byte, additional []byte, buf *[]byte, tbase time.Duration, keyframe bool, discardPadding time.Duration) {
+	var p Packet
+	p.TrackNumber = uint(data[0]) & 0x7f
+	p.Timecode = tbase + time.Millisecond*time.Duration(
+		uint(data[1])<<8+uint(data[2]))
+	p.Invisible = (data[3] & 8) != 0
+	p.Keyframe = keyframe
+	p.Discardable = (data[3] & 1) != 0
+	lacing := (data[3] >> 1) & 3
+	switch lacing {
+	case 0:
+		p.Data = data[4:]
+		p.Additional = additional
+		p.DiscardPadding = discardPadding
+		p.buf = buf
+		r.send(&p)
+	case 1:
+		sz, curr := parseXiphSizes(data)
+		r.sendLaces(&p, data[curr:], sz)
+	case 2:
+		sz, curr := parseFixedSizes(data)
+		r.sendLaces(&p, data[curr:], sz)
+	case 3:
+		sz, curr := parseEBMLSizes(data)
+		r.sendLaces(&p, data[curr:], sz)
+	}
+}
+
+// sendCluster sends the packets in the cluster.
+// If unknownSize is true, elmts is the segment, and the cluster ends at the next top-level element, where elmts is positioned.
+func (r *Reader) sendCluster(elmts *ebml.Element, tbase time.Duration, unknownSize bool) {
+	var err error
+	// found indicates whether the first block of indexTrack in the cluster has been found.
+	var found bool
+	for err == nil && len(r.seek) == 0 {
+		var e *ebml.Element
+		e, err = elmts.Next()
+		var blk []byte
+		var additional []byte
+		var buf *[]byte
+		var key bool
+		var discardPadding time.Duration
+		if err == nil && unknownSize && isTopLevelID(e.Id) {
+			// The next cluster starts.
+			elmts.Seek(e.Offset, io.SeekStart)
+			return
+		}
+		if err == nil {
+			switch e.Id {
+			case 0xa3:
+				// A SimpleBlock is read into a pooled buffer, which is reused after the packet is released.
+				buf = getBlockBuffer(int(e.Size()))
+				blk = *buf
+				_, err = io.ReadFull(e, blk)
+				// A SimpleBlock has a keyframe flag.
+				key = len(blk) > 3 && blk[3]&0x80 != 0
+				if err != nil && err != io.EOF {
+					log.Println(err)
+				}
+			case 0xa0:
+				var bg BlockGroup
+				err = e.Unmarshal(&bg)
+				if err == nil {
+					blk = bg.Block
+					allocstats.Add(allocstats.Demux, len(bg.Block))
+					// A Block in a BlockGroup is a keyframe when it doesn't reference other blocks.
+					key = bg.ReferenceBlock == 0
+					discardPadding = time.Duration(bg.DiscardPadding)
+					for _, m := range bg.BlockAdditions.BlockMore {
+						if m.BlockAddID == 1 {
+							additional = m.BlockAdditional
+						}
+					}
+				}
+				if err != nil && err != io.EOF {
+					log.Println(err)
+				}
+			default:
+				log.Printf("Unexpected packet %x", e.Id)
+			}
+
+			if err == nil && blk != nil && len(blk) > 4 {
+				if !found && !r.live && r.isIndexTrackBlock(blk) {
+					found = true
+					if key {
+						r.addKeyframe(keyframe{blockTimecode(blk, tbase), tbase})
+					}
+				}
+				r.sendBlock(blk, additional, buf, tbase, key, discardPadding)
+			}
+		}
+	}
+}
+
+func (r *Reader) parseClusters(elmts *ebml.Element) {
+	var err error
+	for err == nil {
+		var c Cluster
+		var e *ebml.Element
+		e, err = elmts.Next()
+		if err == nil {
+			err = e.Unmarshal(&c)
+		}
+		if err != nil && err.Error() == "Reached payload" {
+			if !r.live {
+				r.m.Lock()
+				r.index.append(seekEntry{time.Millisecond * time.Duration(c.Timecode), e.Offset})
+				r.m.Unlock()
+			}
+			cluster := err.(ebml.ReachedPayloadError).Element
+			unknownSize, _ := hasUnknownSize(elmts, e.Offset)
+			if unknownSize {
+				// The parser doesn't treat the size as unknown, so read the blocks as the following elements in the segment.
+				cluster = elmts
+			}
+			r.sendCluster(cluster, time.Millisecond*time.Duration(c.Timecode), unknownSize)
+			err = nil
+		}
+		seek := BadTC
+		for len(r.seek) != 0 {
+			seek = <-r.seek
+		}
+		if err == io.EOF {
+			var eofpkt Packet
+			eofpkt.Timecode = BadTC
+			r.send(&eofpkt)
+			seek = <-r.seek
+			if seek != BadTC {
+				err = nil
+			}
+		}
+		if seek != BadTC {
+			r.m.Lock()
+			entry := r.index.search(seek)
+			r.m.Unlock()
+			elmts.Seek(entry.offset, 0)
+			var seekpkt Packet
+			seekpkt.Timecode = seek
+			r.send(&seekpkt)
+		}
+		if seek == shutdown {
+			err = io.EOF
+		}
+	}
+	close(r.Chan)
+}
+
+func newReader(e *ebml.Element, cuepoints []CuePoint, offset int64, indexTrack uint, live bool) *Reader {
+	r := &Reader{
+		Chan:       make(chan Packet, 4),
+		seek:       make(chan time.Duration, 4),
+		index:      newSeekIndex(),
+		offset:     offset,
+		indexTrack: indexTrack,
+		live:       live,
+	}
+	for i, l := 0, len(cuepoints); i < l; i++ {
+		c := cuepoints[i]
+		r.index.append(seekEntry{
+			time.Millisecond * time.Duration(c.CueTime),
+			offset + c.CueTrackPositions[0].CueClusterPosition,
+		})
+	}
+	go r.parseClusters(e)
+	return r
+}
+
+// IsLive reports whether the segment has an unknown size, like a live stream.
+func (r *Reader) IsLive() bool {
+	return r.live
+}
+
+func (r *Reader) Seek(t time.Duration) {
+	r.seek <- t
+}
+
+func (r *Reader) Shutdown() {
+	r.seek <- shutdown
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"runtime"
+	"time"
+)
+
+// deviceLatency returns an estimate of the latency of the audio device's buffer at the sampling frequency,
+// based on the default buffer sizes of Oto, which Ebitengine uses.
+//
+// The data buffered in the audio player is not included, as the audio player's position already excludes it.
+func deviceLatency(samplingFrequency int) time.Duration {
+	frames := func(n int) time.Duration {
+		return time.Duration(int64(n) * int64(time.Second) / int64(samplingFrequency))
+	}
+	switch runtime.GOOS {
+	case "darwin", "ios":
+		// Audio Queue with 4 buffers of 12288 bytes of stereo float32.
+		return frames(4 * 12288 / 8)
+	case "windows":
+		// WASAPI in the shared mode with a 50 ms buffer.
+		return 50 * time.Millisecond
+	case "js":
+		// Web Audio with buffers of 2048 frames.
+		return frames(2048)
+	case "android":
+		// Oboe with a low-latency stream.
+		return 20 * time.Millisecond
+	default:
+		// ALSA with 2 periods of 1024 frames.
+		return frames(2 * 1024)
+	}
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"image"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer/media"
+)
+
+// MediaInfo is the same as media.MediaInfo.
+type MediaInfo = media.MediaInfo
+
+// DecodeOptions is the same as media.DecodeOptions.
+type DecodeOptions = media.DecodeOptions
+
+// VideoFrame is the same as media.VideoFrame.
+type VideoFrame = media.VideoFrame
+
+// Packet is the same as media.Packet.
+type Packet = media.Packet
+
+// VideoDecoder is the same as media.VideoDecoder.
+type VideoDecoder = media.VideoDecoder
+
+// VideoDecoderFactory is the same as media.VideoDecoderFactory.
+type VideoDecoderFactory = media.VideoDecoderFactory
+
+// Probe calls media.Probe.
+func Probe(r io.ReadSeeker) (*MediaInfo, error) {
+	return media.Probe(r)
+}
+
+// Decode calls media.Decode.
+func Decode(r io.ReadSeeker, options *DecodeOptions) error {
+	return media.Decode(r, options)
+}
+
+// ForEachFrame calls media.ForEachFrame.
+func ForEachFrame(r io.ReadSeeker, fn func(img image.Image, pts time.Duration) error) error {
+	return media.ForEachFrame(r, fn)
+}
+
+// FrameAt calls media.FrameAt.
+func FrameAt(r io.ReadSeeker, t time.Duration) (image.Image, error) {
+	return media.FrameAt(r, t)
+}
+
+// VideoFrames calls media.VideoFrames.
+func VideoFrames(r io.ReadSeeker) iter.Seq2[VideoFrame, error] {
+	return media.VideoFrames(r)
+}
+
+// Packets calls media.Packets.
+func Packets(r io.ReadSeeker) iter.Seq2[Packet, error] {
+	return media.Packets(r)
+}
+
+// DecodeAudio calls media.DecodeAudio.
+func DecodeAudio(r io.ReadSeeker, w io.Writer) error {
+	return media.DecodeAudio(r, w)
+}
@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package media
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer/internal/codec"
+	"github.com/hajimehoshi/webmplayer/internal/demux"
+	"github.com/hajimehoshi/webmplayer/internal/webm"
+)
+
+// DecodeOptions represents options for Decode.
+type DecodeOptions struct {
+	// OnVideoFrame is called for each decoded video frame with its presentation timestamp.
+	// frame is an *image.YCbCr, or an *image.NYCbCrA if the frame has an alpha channel.
+	//
+	// If OnVideoFrame is nil, the video track is not decoded.
+	OnVideoFrame func(frame image.Image, pts time.Duration) error
+
+	// OnAudioSamples is called for each decoded audio packet with the presentation timestamp of its first sample.
+	// samples are interleaved stereo values, and are valid only during the call.
+	// A track with more than two channels is mixed down to stereo by the speaker positions of the channels in the Vorbis order,
+	// e.g. front left, center, front right, rear left, rear right and LFE for 5.1.
+	//
+	// If OnAudioSamples is nil, the audio track is not decoded.
+	OnAudioSamples func(samples []float32, samplingFrequency int, pts time.Duration) error
+
+	// OnError is called when decoding a packet fails.
+	// If OnError returns nil, the packet is skipped and decoding continues.
+	//
+	// If OnError is nil, Decode returns the error.
+	OnError func(err error) error
+
+	// VideoThreads is the number of threads to decode video.
+	//
+	// The default (zero) value uses the decoder's default, which is a single thread.
+	VideoThreads int
+}
+
+// Decode decodes the first video and audio tracks of the given WebM stream in file order, as fast as possible.
+//
+// As opposed to Player, Decode doesn't use any clock, and never drops frames.
+// Decode stops and returns the error when a callback returns an error.
+//
+// If options is nil, the default values are used.
+func Decode(r io.ReadSeeker, options *DecodeOptions) error {
+	if options == nil {
+		options = &DecodeOptions{}
+	}
+
+	var meta webm.WebM
+	reader, err := demux.New(r, &meta)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		reader.Shutdown()
+		for range reader.Packets() {
+		}
+	}()
+
+	handleError := func(err error) error {
+		if options.OnError == nil {
+			return err
+		}
+		return options.OnError(err)
+	}
+
+	var vTrack *webm.TrackEntry
+	var vDecoder *codec.VideoDecoder
+	if options.OnVideoFrame != nil {
+		vTrack = meta.FindFirstVideoTrack()
+	}
+	if vTrack != nil {
+		vDecoder, err = codec.NewVideoDecoder(codec.VideoCodec(vTrack.CodecID), &codec.VideoDecoderOptions{
+			Threads: options.VideoThreads,
+		})
+		if err != nil {
+			return err
+		}
+		defer vDecoder.Close()
+	}
+
+	var aTrack *webm.TrackEntry
+	var aDecoder *codec.AudioDecoder
+	var samples []float32
+	if options.OnAudioSamples != nil {
+		aTrack = meta.FindFirstAudioTrack()
+	}
+	if aTrack != nil {
+		aDecoder, err = codec.NewAudioDecoder(aTrack, nil)
+		if err != nil {
+			return err
+		}
+		defer aDecoder.Close()
+	}
+
+	for pkt := range reader.Packets() {
+		if pkt.TrackNumber == 0 {
+			if pkt.Timecode == webm.BadTC {
+				// The end of the stream.
+				return reader.Err()
+			}
+			continue
+		}
+
+		switch {
+		case vTrack != nil && pkt.TrackNumber == vTrack.TrackNumber:
+			if err := vDecoder.Decode(pkt.Data, pkt.Additional); err != nil {
+				if err := handleError(err); err != nil {
+					return err
+				}
+				continue
+			}
+			var iter codec.FrameIter
+			for img := vDecoder.NextFrame(&iter); img != nil; img = vDecoder.NextFrame(&iter) {
+				if err := options.OnVideoFrame(img, pkt.Timecode); err != nil {
+					return err
+				}
+			}
+
+		case aTrack != nil && pkt.TrackNumber == aTrack.TrackNumber:
+			if len(pkt.Data) == 0 {
+				continue
+			}
+			var pts time.Duration
+			samples, pts, err = aDecoder.Decode(samples[:0], pkt.Data, pkt.Timecode, pkt.DiscardPadding)
+			if err != nil {
+				if err := handleError(err); err != nil {
+					return err
+				}
+				continue
+			}
+			if len(samples) == 0 {
+				continue
+			}
+			if err := options.OnAudioSamples(samples, aDecoder.SamplingFrequency(), pts); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ForEachFrame decodes the first video track of the given WebM, Ogg or IVF stream, and calls fn for each frame in file order.
+//
+// ForEachFrame is a shorthand for Decode with only OnVideoFrame, and is deterministic: no frame is dropped, and no clock is used.
+// img can be retained after fn returns.
+// ForEachFrame stops and returns the error when fn returns an error.
+func ForEachFrame(r io.ReadSeeker, fn func(img image.Image, pts time.Duration) error) error {
+	return Decode(r, &DecodeOptions{
+		OnVideoFrame: fn,
+	})
+}
+
+// FrameAt decodes and returns the video frame shown at t in the given WebM, Ogg or IVF stream, without creating a Player.
+// This is useful to generate thumbnails.
+//
+// FrameAt seeks to the keyframe before t and decodes the frames up to t.
+// If t is before the first frame, the first frame is returned. If t is after the last frame, the last frame is returned.
+//
+// The returned image is an *image.YCbCr, or an *image.NYCbCrA if the frame has an alpha channel, without the cropped edges.
+func FrameAt(r io.ReadSeeker, t time.Duration) (image.Image, error) {
+	var meta webm.WebM
+	reader, err := demux.New(r, &meta)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		reader.Shutdown()
+		for range reader.Packets() {
+		}
+	}()
+
+	track := meta.FindFirstVideoTrack()
+	if track == nil {
+		return nil, fmt.Errorf("webmplayer: no video track")
+	}
+	decoder, err := codec.NewVideoDecoder(codec.VideoCodec(track.CodecID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer decoder.Close()
+
+	visible := func(img image.Image) image.Image {
+		return codec.SubFrame(img, codec.CropBounds(img.Bounds(), int(track.PixelCropLeft), int(track.PixelCropTop), int(track.PixelCropRight), int(track.PixelCropBottom)))
+	}
+
+	reader.Seek(t)
+	seeking := true
+
+	var last image.Image
+	for pkt := range reader.Packets() {
+		if pkt.TrackNumber == 0 {
+			if pkt.Timecode != webm.BadTC {
+				// The marker after seeking.
+				seeking = false
+				continue
+			}
+			if seeking {
+				// The reader reached the end before processing the seek.
+				continue
+			}
+			if err := reader.Err(); err != nil {
+				return nil, err
+			}
+			break
+		}
+		if seeking || pkt.TrackNumber != track.TrackNumber {
+			continue
+		}
+
+		if err := decoder.Decode(pkt.Data, pkt.Additional); err != nil {
+			return nil, err
+		}
+		var iter codec.FrameIter
+		for img := decoder.NextFrame(&iter); img != nil; img = decoder.NextFrame(&iter) {
+			if pkt.Timecode > t {
+				if last == nil {
+					return visible(img), nil
+				}
+				return visible(last), nil
+			}
+			last = img
+		}
+	}
+
+	if last == nil {
+		return nil, fmt.Errorf("webmplayer: no video frame")
+	}
+	return visible(last), nil
+}
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+// Package media demuxes and decodes WebM, Ogg and IVF streams without Ebitengine.
+//
+// The package has no clock and no graphics or audio output, so that it can be used in tools and servers,
+// e.g. to make thumbnails or to analyze streams. webmplayer's Player is an adapter of the same decoders for Ebitengine.
+//
+// The functions and the types are also available in webmplayer with the same names.
+package media
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package media
+
+import (
+	"errors"
+	"image"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer/internal/demux"
+	"github.com/hajimehoshi/webmplayer/internal/webm"
+)
+
+// VideoFrame represents a decoded video frame.
+type VideoFrame struct {
+	// Image is an *image.YCbCr, or an *image.NYCbCrA if the frame has an alpha channel.
+	Image image.Image
+
+	// PTS is the presentation timestamp.
+	PTS time.Duration
+}
+
+// Packet represents a demuxed packet.
+type Packet struct {
+	// TrackNumber is the number of the track in the container.
+	TrackNumber int
+
+	// PTS is the presentation timestamp.
+	// PTS is -1 if the packet doesn't have its own timestamp, e.g. a laced audio packet except for the first one.
+	PTS time.Duration
+
+	// Keyframe reports whether the packet is marked as a keyframe by the container.
+	// In WebM, this is the keyframe flag of a SimpleBlock, or the absence of a ReferenceBlock in a BlockGroup.
+	// All the audio packets of an Ogg stream are keyframes.
+	Keyframe bool
+
+	Data []byte
+}
+
+// errStopIteration is returned from a callback to stop decoding when an iteration stops.
+var errStopIteration = errors.New("webmplayer: iteration stopped")
+
+// VideoFrames returns an iterator over the decoded frames of the first video track in the given WebM, Ogg or IVF stream.
+//
+// The frames are decoded in the same way as Decode.
+// If an error happens, the iterator yields the error and stops.
+func VideoFrames(r io.ReadSeeker) iter.Seq2[VideoFrame, error] {
+	return func(yield func(VideoFrame, error) bool) {
+		err := Decode(r, &DecodeOptions{
+			OnVideoFrame: func(frame image.Image, pts time.Duration) error {
+				if !yield(VideoFrame{Image: frame, PTS: pts}, nil) {
+					return errStopIteration
+				}
+				return nil
+			},
+		})
+		if err != nil && err != errStopIteration {
+			yield(VideoFrame{}, err)
+		}
+	}
+}
+
+// Packets returns an iterator over the packets of all the tracks in the given WebM, Ogg or IVF stream in file order, without decoding them.
+//
+// If an error happens, the iterator yields the error and stops.
+func Packets(r io.ReadSeeker) iter.Seq2[Packet, error] {
+	return func(yield func(Packet, error) bool) {
+		var meta webm.WebM
+		reader, err := demux.New(r, &meta)
+		if err != nil {
+			yield(Packet{}, err)
+			return
+		}
+		defer func() {
+			reader.Shutdown()
+			for range reader.Packets() {
+			}
+		}()
+
+		for pkt := range reader.Packets() {
+			if pkt.TrackNumber == 0 {
+				if pkt.Timecode == webm.BadTC {
+					// The end of the stream.
+					if err := reader.Err(); err != nil {
+						yield(Packet{}, err)
+					}
+					return
+				}
+				continue
+			}
+			p := Packet{
+				TrackNumber: int(pkt.TrackNumber),
+				PTS:         pkt.Timecode,
+				Keyframe:    pkt.Keyframe,
+				Data:        pkt.Data,
+			}
+			if pkt.Timecode == webm.BadTC {
+				p.PTS = -1
+			}
+			if !yield(p, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package media
+
+import (
+	"io"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer/internal/demux"
+	"github.com/hajimehoshi/webmplayer/internal/webm"
+)
+
+// MediaInfo represents information of a WebM stream.
+type MediaInfo struct {
+	Duration time.Duration
+
+	// VideoCodecID is empty if there is no video track.
+	VideoCodecID string
+
+	// VideoWidth and VideoHeight are the display size, which is the same as Player.VideoSize.
+	VideoWidth  int
+	VideoHeight int
+
+	// AudioCodecID is empty if there is no audio track.
+	AudioCodecID           string
+	AudioChannels          int
+	AudioSamplingFrequency int
+}
+
+// Probe reads the headers of the given WebM, Ogg or IVF stream and returns its information without decoding it.
+func Probe(r io.ReadSeeker) (*MediaInfo, error) {
+	var meta webm.WebM
+	reader, err := demux.New(r, &meta)
+	if err != nil {
+		return nil, err
+	}
+	reader.Shutdown()
+	for range reader.Packets() {
+	}
+
+	info := &MediaInfo{
+		Duration: time.Duration(meta.Duration * float64(meta.TimecodeScale)),
+	}
+	if t := meta.FindFirstVideoTrack(); t != nil {
+		info.VideoCodecID = t.CodecID
+		info.VideoWidth, info.VideoHeight = demux.DisplaySize(&t.Video)
+	}
+	if t := meta.FindFirstAudioTrack(); t != nil {
+		info.AudioCodecID = t.CodecID
+		info.AudioChannels = int(t.Channels)
+		info.AudioSamplingFrequency = int(t.SamplingFrequency)
+	}
+	return info, nil
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package media
+
+import "image"
+
+// VideoDecoder is a video decoder to replace the built-in libvpx decoder, e.g. a platform hardware decoder like VideoToolbox, MediaCodec or D3D11VA.
+//
+// The methods are called from a single decoding goroutine.
+type VideoDecoder interface {
+	// Decode decodes a packet. data is valid only during the call.
+	Decode(data []byte) error
+
+	// NextFrame returns the next frame decoded from the last packet, or nil if there is no more frame.
+	//
+	// The frame must be an *image.YCbCr or an *image.NYCbCrA, and is owned by the player after NextFrame returns.
+	NextFrame() image.Image
+
+	// Reset discards the state of the previous frames, e.g. after a corrupt packet. The next packet is a keyframe.
+	Reset() error
+
+	// Close releases the resources of the decoder.
+	Close() error
+}
+
+// VideoDecoderFactory creates a VideoDecoder for a video track.
+// codecID is the codec ID in the container, like "V_VP8" or "V_VP9", and width and height are the frame size in the container.
+//
+// If the decoder is unavailable, e.g. the codec or the platform is not supported, VideoDecoderFactory returns nil and a nil error.
+// Then the built-in libvpx decoder is used.
+// If VideoDecoderFactory returns an error, the built-in decoder is used too, and the error is passed to webmplayer.PlayerOptions.OnWarning.
+//
+// The built-in decoder decodes only VP8 and VP9, so an AV1 ("V_AV1") track can be played only with a decoder from VideoDecoderFactory.
+type VideoDecoderFactory func(codecID string, width, height int) (VideoDecoder, error)
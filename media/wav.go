@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package media
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// wavHeaderSize is the size in bytes of the WAV header before the samples.
+const wavHeaderSize = 44
+
+// DecodeAudio decodes the first audio track of the given WebM or Ogg stream, and writes it to w as a WAV stream
+// of 16-bit stereo PCM at the sampling frequency of the track.
+//
+// DecodeAudio decodes as fast as possible without any clock, in the same way as Decode.
+//
+// If w is an io.WriteSeeker, e.g. an *os.File, the sizes in the WAV header are updated after all the samples are written.
+// Otherwise, the sizes are unknown (0xffffffff), which most WAV readers treat as a stream up to the end.
+func DecodeAudio(r io.ReadSeeker, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	var sampleRate int
+	var dataSize int64
+	if err := Decode(r, &DecodeOptions{
+		OnAudioSamples: func(samples []float32, samplingFrequency int, pts time.Duration) error {
+			if sampleRate == 0 {
+				sampleRate = samplingFrequency
+				if err := writeWAVHeader(bw, sampleRate, math.MaxUint32); err != nil {
+					return err
+				}
+			}
+			var buf [2]byte
+			for _, s := range samples {
+				v := int16(max(-1, min(s, 1)) * math.MaxInt16)
+				binary.LittleEndian.PutUint16(buf[:], uint16(v))
+				if _, err := bw.Write(buf[:]); err != nil {
+					return err
+				}
+			}
+			dataSize += int64(2 * len(samples))
+			return nil
+		},
+	}); err != nil {
+		return err
+	}
+	if sampleRate == 0 {
+		return fmt.Errorf("webmplayer: no audio samples")
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	ws, ok := w.(io.WriteSeeker)
+	if !ok || dataSize > math.MaxUint32-(wavHeaderSize-8) {
+		return nil
+	}
+	// Rewrite the header with the actual sizes.
+	if _, err := ws.Seek(-(wavHeaderSize + dataSize), io.SeekCurrent); err != nil {
+		return err
+	}
+	if err := writeWAVHeader(ws, sampleRate, uint32(dataSize)); err != nil {
+		return err
+	}
+	if _, err := ws.Seek(dataSize, io.SeekCurrent); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeWAVHeader writes the header of a WAV stream of 16-bit stereo PCM with dataSize bytes of samples.
+// If dataSize is math.MaxUint32, the sizes are written as unknown.
+func writeWAVHeader(w io.Writer, sampleRate int, dataSize uint32) error {
+	const (
+		channels      = 2
+		bitsPerSample = 16
+		blockAlign    = channels * bitsPerSample / 8
+	)
+	riffSize := uint32(math.MaxUint32)
+	if dataSize != math.MaxUint32 {
+		riffSize = wavHeaderSize - 8 + dataSize
+	}
+
+	var h [wavHeaderSize]byte
+	copy(h[0:], "RIFF")
+	binary.LittleEndian.PutUint32(h[4:], riffSize)
+	copy(h[8:], "WAVE")
+	copy(h[12:], "fmt ")
+	binary.LittleEndian.PutUint32(h[16:], 16)
+	// WAVE_FORMAT_PCM
+	binary.LittleEndian.PutUint16(h[20:], 1)
+	binary.LittleEndian.PutUint16(h[22:], channels)
+	binary.LittleEndian.PutUint32(h[24:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(h[28:], uint32(sampleRate*blockAlign))
+	binary.LittleEndian.PutUint16(h[32:], blockAlign)
+	binary.LittleEndian.PutUint16(h[34:], bitsPerSample)
+	copy(h[36:], "data")
+	binary.LittleEndian.PutUint32(h[40:], dataSize)
+	_, err := w.Write(h[:])
+	return err
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"io"
+	"math"
+	"sync/atomic"
+	"unsafe"
+)
+
+// panStream applies the gains of the left and the right channels to a stereo float32 stream.
+type panStream struct {
+	src io.ReadSeeker
+
+	// left and right are the gains in math.Float32bits.
+	left  atomic.Uint32
+	right atomic.Uint32
+}
+
+func newPanStream(src io.ReadSeeker) *panStream {
+	p := &panStream{
+		src: src,
+	}
+	p.left.Store(math.Float32bits(1))
+	p.right.Store(math.Float32bits(1))
+	return p
+}
+
+func (p *panStream) Read(buf []byte) (int, error) {
+	n, err := p.src.Read(buf)
+	left := math.Float32frombits(p.left.Load())
+	right := math.Float32frombits(p.right.Load())
+	if left == 1 && right == 1 {
+		return n, err
+	}
+	samples := unsafe.Slice((*float32)(unsafe.Pointer(unsafe.SliceData(buf))), n/4)
+	for i := 0; i+1 < len(samples); i += 2 {
+		samples[i] *= left
+		samples[i+1] *= right
+	}
+	return n, err
+}
+
+func (p *panStream) Seek(offset int64, whence int) (int64, error) {
+	return p.src.Seek(offset, whence)
+}
+
+// SetGains sets the linear gains of the left and the right channels.
+func (p *panStream) SetGains(left, right float64) {
+	p.left.Store(math.Float32bits(float32(left)))
+	p.right.Store(math.Float32bits(float32(right)))
+}
+
+// Gains returns the linear gains of the left and the right channels.
+func (p *panStream) Gains() (float64, float64) {
+	return float64(math.Float32frombits(p.left.Load())), float64(math.Float32frombits(p.right.Load()))
+}
@@ -4,22 +4,113 @@
 package webmplayer
 
 import (
+	"context"
 	"fmt"
+	"image"
+	"image/color"
 	"io"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/ebml-go/webm"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
+
+	"github.com/hajimehoshi/webmplayer/internal/allocstats"
+	"github.com/hajimehoshi/webmplayer/internal/codec"
+	"github.com/hajimehoshi/webmplayer/internal/demux"
+	"github.com/hajimehoshi/webmplayer/internal/webm"
 )
 
+// Player plays video and audio streams in sync.
 type Player struct {
 	width  int
 	height int
 
+	// orientation is the clockwise rotation in degrees to display the video. rotate indicates whether Draw applies it.
+	orientation int
+	rotate      bool
+
+	// streams is the streams being played.
+	streams     []*stream
 	videoStream *videoStream
 	audioStream *audioStream
+
+	// httpReader is the reader of the stream created by NewPlayerFromURL, which is closed with the player.
+	httpReader *httpReader
+
+	// videoOwner is the stream of videoStream.
+	videoOwner *stream
+
+	// audioTracks is the streams with the selectable audio tracks.
+	audioTracks []*stream
+	audioTrack  int
+
+	// audioSources is the sources of rateStream for audioTracks,
+	// which are resampled to the sampling frequency of the first audio track if needed.
+	audioSources []io.ReadSeeker
+
 	audioPlayer *audio.Player
+	rateStream  *rateStream
+	audioTap    *audioTap
+	panStream   *panStream
+
+	// audioContextSetup is how the audio context was set up.
+	audioContextSetup AudioContextSetup
+
+	// stopAudioAtEnd indicates whether the audio player stops at the end of the audio.
+	stopAudioAtEnd bool
+
+	// audioLatency is the output latency of the audio device to compensate.
+	audioLatency time.Duration
+	// audioSeekPosition is the audio player's position at the last seek.
+	// The position is held there until the audio from the seek is heard.
+	audioSeekPosition time.Duration
+
+	// startTime and startPosition are used as the clock when there is no audio.
+	startTime     time.Time
+	startPosition time.Duration
+
+	// scrubbing indicates whether the position is held by Scrub.
+	scrubbing bool
+
+	// paused indicates whether the playback is paused by Pause.
+	paused bool
+
+	// maxLiveLatency is PlayerOptions.MaxLiveLatency.
+	maxLiveLatency time.Duration
+
+	playbackRate float64
+
+	// watchTime is the played duration until watchStart, where the current continuous playback started.
+	watchTime        time.Duration
+	watchStart       time.Duration
+	furthestPosition time.Duration
+
+	// ended and endPosition are the state and the position when the player finished.
+	ended       bool
+	endPosition time.Duration
+
+	// vertices is a buffer for DrawTriangles.
+	vertices []ebiten.Vertex
+
+	// diagnostics indicates whether the diagnostics mode is enabled. warned is the kinds of the warnings already logged.
+	diagnostics bool
+	warned      map[diagnostic]struct{}
+
+	sceneChangeCallback func(event *SceneChangeEvent)
+	sceneChangeEvents   []SceneChangeEvent
+
+	// shownFrames is the number of the frames shown at the last Update.
+	shownFrames         int64
+	frameChangeCallback func(pts time.Duration)
+
+	// decodedSize is the decoded size notified to decodedSizeCallback last time.
+	decodedSize         image.Point
+	decodedSizeCallback func(width, height int)
 
 	videoDuration time.Duration
 	videoCodecID  string
@@ -27,8 +118,326 @@ type Player struct {
 	audioCodecID  string
 }
 
+// PlayerOptions represents options for New.
+type PlayerOptions struct {
+	// VideoThreads is the number of threads to decode video.
+	// Multiple threads are useful for high resolution videos, especially VP9.
+	//
+	// The default (zero) value uses the decoder's default, which is a single thread.
+	VideoThreads int
+
+	// VP8Postproc is the postprocessing of VP8 frames, which reduces the artifacts of low-bitrate videos at the cost of CPU time.
+	// VP8Postproc is ignored for the other codecs.
+	//
+	// The default (zero) value is nil, which disables postprocessing.
+	VP8Postproc *VP8PostprocOptions
+
+	// VideoDecoderFactory creates a video decoder to replace the built-in libvpx decoder, e.g. a platform hardware decoder.
+	// If the factory returns no decoder, the built-in decoder is used. See VideoDecoderFactory for details.
+	//
+	// VideoThreads and VP8Postproc are not applied to the decoders created by the factory.
+	//
+	// The default (zero) value is nil, which always uses the built-in decoder.
+	VideoDecoderFactory VideoDecoderFactory
+
+	// FrameSink presents the video frames instead of Ebitengine.
+	// With a FrameSink, no frame is uploaded to the GPU, and Draw, RenderTo, DrawTriangles and CurrentFrame do nothing.
+	//
+	// The default (zero) value is nil, which presents the frames with Ebitengine by Draw.
+	FrameSink FrameSink
+
+	// ToneMapHDR specifies whether HDR videos are tone-mapped to SDR, so that they don't look washed out or blown out on SDR displays.
+	// A video is treated as HDR when its Colour element has the PQ or HLG transfer characteristics and the BT.2020 primaries.
+	// The peak luminance for PQ is taken from MaxCLL or the mastering metadata, or 1000 nits if neither exists.
+	//
+	// Draw and Screenshot use the tone-mapped colors, while the frames passed to callbacks keep the original values.
+	//
+	// The default (zero) value is false.
+	ToneMapHDR bool
+
+	// FrameDropPolicy is the policy to drop video frames that are late.
+	//
+	// The default (zero) value is FrameDropLate.
+	FrameDropPolicy FrameDropPolicy
+
+	// CatchUpFrames is the number of consecutive late video frames after which the player skips to the next keyframe.
+	// The frames until the keyframe are dropped without decoding, so that the video recovers on a slow machine instead of lagging permanently.
+	// CatchUpFrames is ignored for FrameDropNever.
+	//
+	// The default (zero) value is 0, which decodes every late frame.
+	CatchUpFrames int
+
+	// AdaptiveDecoding specifies whether the player reduces the decoding work while video frames are late, e.g. on a weak CPU.
+	//
+	// While frames are late, the late frames that no other frame refers to are skipped without decoding,
+	// and the loop filter of VP9 is skipped, which makes the frames blocky until the next keyframe.
+	// AdaptiveDecoding doesn't work with FrameDropNever, as no frame is treated as late.
+	//
+	// The default (zero) value is false.
+	AdaptiveDecoding bool
+
+	// MaxDecodeAhead is how far ahead of the current position video packets can be demuxed and queued for decoding.
+	// A limit makes the memory usage predictable regardless of the frame rate, and reduces the packets to discard after seeking.
+	//
+	// The audio in the same stream as the video is also demuxed only up to around this duration ahead,
+	// so a too short duration like less than 200 milliseconds might cause audio stutters.
+	//
+	// The default (zero) value is no limit, and up to 32 packets are queued.
+	MaxDecodeAhead time.Duration
+
+	// MaxLiveLatency is how far the playback of a live stream can fall behind the live edge, the newest data of the stream.
+	// When the playback falls further behind, e.g. after pausing or a network stall, the player skips to the live edge.
+	// MaxLiveLatency is ignored for the other streams. See Player.IsLive.
+	//
+	// The default (zero) value is no limit, which never skips.
+	MaxLiveLatency time.Duration
+
+	// ErrorResilient specifies whether the video keeps playing when a packet fails to decode, e.g. in a partially corrupt file.
+	//
+	// If ErrorResilient is true, the failure is logged and counted in VideoStats.CorruptFrames,
+	// and the packets until the next keyframe are skipped. The decoder is reset at the keyframe, and the video resumes from there.
+	// If ErrorResilient is false, the video stops and Update returns the error.
+	//
+	// The default (zero) value is false.
+	ErrorResilient bool
+
+	// LateFrameThreshold is how far behind the current position a video frame can be to be shown.
+	// A frame later than this is treated as late, and is handled by FrameDropPolicy.
+	//
+	// The default (zero) value is 1/60 seconds.
+	LateFrameThreshold time.Duration
+
+	// ResampleQuality is the quality of audio resampling to change the playback rate without preserving the pitch,
+	// and to convert the sampling frequency to the existing audio context's, if any.
+	// ResampleQualityPolyphase is recommended to convert to a lower sampling frequency, as it removes aliasing.
+	// A lower quality is useful to save CPU time on low-end devices.
+	//
+	// The default (zero) value is ResampleQualityLinear.
+	ResampleQuality ResampleQuality
+
+	// SeekCrossfade is the duration to cross-fade the audio before and after a seek, to avoid a click from the discontinuity of the waveform.
+	// A negative value disables the cross-fade, which is useful when the audio output must be sample-exact.
+	//
+	// The default (zero) value is 5 milliseconds.
+	SeekCrossfade time.Duration
+
+	// StopAudioAtEnd indicates whether the audio player stops at the end of the audio after a short tail of silence,
+	// instead of playing silence forever.
+	// With this, the position stays at the end, and IsFinished reports true after the audio is played to the end.
+	// The audio player plays again by a seek.
+	//
+	// The default (zero) value is false.
+	StopAudioAtEnd bool
+
+	// AudioLatency is the output latency of the audio device, from when the audio is sent to the device until it is heard.
+	// The position, and then the video, is delayed by the latency to synchronize with the heard audio.
+	// This is useful to set a latency calibrated by the application, e.g. with the user's input, as the latency depends on the device.
+	// A negative value disables the compensation.
+	//
+	// The default (zero) value is an estimate for the platform's default audio buffer. See also Player.AudioLatency.
+	AudioLatency time.Duration
+
+	// AudioBufferSize is the size of the audio player's buffer.
+	// A smaller buffer reduces the audio latency for interactive use, and a larger buffer makes the playback robust against hiccups.
+	// The data in the buffer is not included in the position.
+	//
+	// The default (zero) value is 0, which uses the default of Ebitengine's audio player.
+	AudioBufferSize time.Duration
+
+	// AudioChunkSize is the duration of the audio processed at a time to change the playback rate or the sampling frequency.
+	// A smaller chunk reduces the latency of SetPlaybackRate, and a larger chunk reduces the overhead.
+	//
+	// The default (zero) value is 0, which means 1024 frames.
+	AudioChunkSize time.Duration
+
+	// AudioContext is the audio context to play the audio, which is owned by the application.
+	// The application can play its own sounds in the same context along with the video's audio.
+	// If the context's sampling frequency differs from the audio's, the audio is resampled.
+	//
+	// The default (zero) value is nil, which uses the existing audio context if any, or creates a new one.
+	AudioContext *audio.Context
+
+	// AudioTracks is additional audio-only streams, like dubs in other languages.
+	// The audio track in the streams given to New, if any, is the first audio track, and AudioTracks follow.
+	// The audio track can be switched by SetAudioTrack.
+	// An audio track with a different sampling frequency from the first audio track is resampled, and OnWarning is called.
+	//
+	// The default (zero) value is nil.
+	AudioTracks []io.ReadSeeker
+
+	// AudioGains is the gains in decibels for the audio tracks, keyed by the audio track indices.
+	// This is useful to match the loudness of dubs in different languages.
+	//
+	// An audio track without an entry uses the GAIN tag for the track in the stream, like "-3.5 dB", if any.
+	// The tag can be added at mux time, e.g. by mkvpropedit.
+	//
+	// An audio track without the GAIN tag is normalized by the ReplayGain tags (REPLAYGAIN_GAIN or REPLAYGAIN_TRACK_GAIN with the peak)
+	// or the R128_TRACK_GAIN tag if any, unless IgnoreLoudnessTags is true.
+	//
+	// The default (zero) value is nil.
+	AudioGains map[int]float64
+
+	// IgnoreLoudnessTags specifies whether the ReplayGain and R128 tags are ignored.
+	//
+	// The default (zero) value is false, which normalizes the loudness of the audio tracks by the tags.
+	IgnoreLoudnessTags bool
+
+	// TraceWriter is a writer to record the timeline of the demuxed packets, which is useful to diagnose sync issues.
+	//
+	// The timeline is written in CSV with a header line. The columns are:
+	//
+	//   - time: the wall-clock time in seconds since the player was created
+	//   - stream: the serial number of the input stream, starting with 0
+	//   - track: the track number, or 0 for markers
+	//   - type: video, audio, other, seek (a marker after seeking) or eos (a marker at the end)
+	//   - pts: the presentation timestamp in seconds, or empty if the packet doesn't have its own timestamp
+	//   - size: the data size in bytes
+	//   - keyframe: 1 if the packet is marked as a keyframe, or 0 otherwise
+	//
+	// TraceWriter is called from multiple goroutines, but not concurrently. Nothing is written after TraceWriter returns an error.
+	//
+	// The default (zero) value is nil, which disables tracing.
+	TraceWriter io.Writer
+
+	// ProfileLabel is the value of the "webmplayer.player" pprof label of the player's goroutines,
+	// which identifies the player in CPU profiles and goroutine dumps of a game playing several videos.
+	//
+	// The goroutines also have the following labels:
+	//
+	//   - webmplayer.goroutine: demux or video
+	//   - webmplayer.file: the base name of the file, if the stream has a Name method like *os.File
+	//   - webmplayer.track: the track number of the video decoded by the goroutine
+	//
+	// The default (zero) value is an empty string, which omits the webmplayer.player label.
+	ProfileLabel string
+
+	// OnWarning is called when the player recovers from a problem in a stream, like inconsistent or missing metadata.
+	// OnWarning is called synchronously during New.
+	//
+	// The default (zero) value is nil, which ignores warnings.
+	OnWarning func(err error)
+
+	// IgnoreOrientation specifies whether Draw and VideoSize ignore the rotation of the video in the metadata.
+	// This is useful to rotate the video by yourself with Orientation.
+	//
+	// The default (zero) value is false, which draws the video upright.
+	IgnoreOrientation bool
+
+	// Diagnostics specifies whether the player logs warnings about likely mistakes in integration,
+	// like drawing with a degenerate GeoM, which would otherwise draw nothing silently.
+	// Each kind of warning is logged only once with the standard logger.
+	//
+	// The default (zero) value is false.
+	Diagnostics bool
+}
+
+// VP8PostprocOptions represents options for the postprocessing of VP8 frames.
+type VP8PostprocOptions struct {
+	// Deblock specifies whether the edges of blocks are smoothed.
+	//
+	// The default (zero) value is false.
+	Deblock bool
+
+	// DemacroblockLevel is the strength to smooth the edges of macroblocks, which is useful for heavily compressed videos.
+	// The level is typically in [1, 16].
+	//
+	// The default (zero) value is 0, which disables demacroblocking.
+	DemacroblockLevel int
+
+	// NoiseLevel is the strength of the noise added to hide banding and blocks.
+	// The level is typically in [1, 16].
+	//
+	// The default (zero) value is 0, which adds no noise.
+	NoiseLevel int
+}
+
+// FrameDropPolicy represents how a player handles video frames that are late.
+type FrameDropPolicy int
+
+const (
+	// FrameDropLate decodes all the frames, and doesn't show late frames.
+	FrameDropLate FrameDropPolicy = iota
+
+	// FrameDropNever shows all the frames even if they are late.
+	// The video lags behind until it catches up, which is useful when every frame matters, like slides.
+	FrameDropNever
+
+	// FrameDropToKeyframe stops decoding frames once a frame is late, and resumes at the next keyframe.
+	// This saves the decoding time when the decoder cannot keep up, while the video freezes until the next keyframe.
+	FrameDropToKeyframe
+)
+
+// NewPlayerOptions represents options for NewPlayerWithOptions.
+//
+// Deprecated: as of v1. Use PlayerOptions instead.
+type NewPlayerOptions = PlayerOptions
+
+// NewPlayer creates a new player.
+//
+// Deprecated: as of v1. Use New instead.
 func NewPlayer(streams ...io.ReadSeeker) (*Player, error) {
-	stream1, stream2, err := discoverStreams(streams...)
+	return New(nil, streams...)
+}
+
+// NewPlayerWithOptions creates a new player with the given options.
+//
+// Deprecated: as of v1. Use New instead.
+func NewPlayerWithOptions(options *PlayerOptions, streams ...io.ReadSeeker) (*Player, error) {
+	return New(options, streams...)
+}
+
+// New creates a new player with the given options.
+//
+// A stream is a WebM file, an Ogg file with Vorbis or Opus audio, or an IVF file with VP8 or VP9 frames.
+// A Matroska file can have FLAC (A_FLAC), MP3 (A_MPEG/L3) or uncompressed PCM (A_PCM) audio too.
+// Up to two streams are used: a stream with video and another stream with audio, in any order, or one stream with both.
+// A live WebM stream, which has an unknown size and continues indefinitely, is supported too. See IsLive.
+// A stream received in chunks, e.g. via WebSocket, can be passed as a ChunkSource.
+// An audio-only stream like a Matroska audio (.mka) file is played without any video setup.
+// In this case, VideoSize returns zeros and Draw does nothing.
+//
+// For audio, New uses PlayerOptions.AudioContext or the existing audio context if any, and resamples the audio if the context's sampling frequency differs.
+// Otherwise, New creates an audio context at the audio's sampling frequency. See also AudioContextSetup.
+//
+// If options is nil, the default values are used.
+func New(options *PlayerOptions, streams ...io.ReadSeeker) (*Player, error) {
+	if options == nil {
+		options = &PlayerOptions{}
+	}
+
+	streamOptions := &streamOptions{
+		video: videoStreamOptions{
+			decoder: codec.VideoDecoderOptions{
+				Threads: options.VideoThreads,
+			},
+			lateThreshold:  options.LateFrameThreshold,
+			dropPolicy:     options.FrameDropPolicy,
+			catchUpFrames:  options.CatchUpFrames,
+			adaptive:       options.AdaptiveDecoding,
+			resilient:      options.ErrorResilient,
+			maxDecodeAhead: options.MaxDecodeAhead,
+			toneMap:        options.ToneMapHDR,
+			newDecoder:     options.VideoDecoderFactory,
+			sink:           options.FrameSink,
+		},
+		warn:         options.OnWarning,
+		audioEOF:     options.StopAudioAtEnd,
+		profileLabel: options.ProfileLabel,
+	}
+	if options.TraceWriter != nil {
+		streamOptions.tracer = newPacketTracer(options.TraceWriter)
+	}
+	if options.VP8Postproc != nil {
+		// Copy the options as the decoders refer to them when they are reset.
+		pp := options.VP8Postproc
+		streamOptions.video.decoder.VP8Postproc = &codec.VP8Postproc{
+			Deblock:           pp.Deblock,
+			DemacroblockLevel: pp.DemacroblockLevel,
+			NoiseLevel:        pp.NoiseLevel,
+		}
+	}
+
+	stream1, stream2, err := discoverStreams(streamOptions, streams...)
 	if err != nil {
 		return nil, err
 	}
@@ -40,64 +449,252 @@ func NewPlayer(streams ...io.ReadSeeker) (*Player, error) {
 	videoMeta := stream1.Meta()
 	videoTrack := videoMeta.FindFirstVideoTrack()
 
-	var audioStream *audioStream
-	var audioMeta *webm.WebM
-	if stream2 != nil {
-		audioStream = stream2.AudioStream()
-		audioMeta = stream2.Meta()
-	} else {
-		audioStream = stream1.AudioStream()
-		audioMeta = stream1.Meta()
-	}
-	audioTrack := audioMeta.FindFirstAudioTrack()
-
 	var w, h int
+	var orientation int
 	var videoCodecID string
+	var videoDuration time.Duration
 	if videoTrack != nil {
-		w, h = int(videoTrack.DisplayWidth), int(videoTrack.DisplayHeight)
+		w, h = demux.DisplaySize(&videoTrack.Video)
+		orientation, err = videoOrientation(videoMeta, videoTrack)
+		if err != nil {
+			return nil, err
+		}
 		videoCodecID = videoTrack.CodecID
+		videoDuration = videoMeta.GetDuration()
 	}
 
-	var audioCodecID string
-	if audioTrack != nil {
-		audioCodecID = audioTrack.CodecID
+	v := &Player{
+		streams:        []*stream{stream1},
+		videoOwner:     stream1,
+		width:          w,
+		height:         h,
+		orientation:    orientation,
+		rotate:         !options.IgnoreOrientation,
+		videoStream:    videoStream,
+		videoDuration:  videoDuration,
+		videoCodecID:   videoCodecID,
+		playbackRate:   1,
+		diagnostics:    options.Diagnostics,
+		maxLiveLatency: options.MaxLiveLatency,
+	}
+	if stream2 != nil {
+		v.streams = append(v.streams, stream2)
 	}
 
-	v := &Player{
-		width:         w,
-		height:        h,
-		videoStream:   videoStream,
-		audioStream:   audioStream,
-		videoDuration: videoMeta.GetDuration(),
-		videoCodecID:  videoCodecID,
-		audioDuration: audioMeta.GetDuration(),
-		audioCodecID:  audioCodecID,
-	}
-
-	if audioStream != nil {
-		ctx := audio.NewContext(audioStream.SamplingFrequency())
-		p, err := ctx.NewPlayerF32(audioStream)
+	audioOwner := stream1
+	if stream2 != nil {
+		audioOwner = stream2
+	}
+	if audioOwner.AudioStream() != nil {
+		v.audioTracks = append(v.audioTracks, audioOwner)
+	}
+	for _, r := range options.AudioTracks {
+		s, err := newStream(r, streamOptions)
+		if err != nil {
+			return nil, err
+		}
+		if s.VideoStream() != nil || s.AudioStream() == nil {
+			return nil, fmt.Errorf("webmplayer: an audio track must be an audio-only stream")
+		}
+		v.audioTracks = append(v.audioTracks, s)
+	}
+	for i, s := range v.audioTracks {
+		gain, ok := options.AudioGains[i]
+		if !ok {
+			var err error
+			gain, ok, err = s.AudioGainTag()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !ok && !options.IgnoreLoudnessTags {
+			var err error
+			gain, ok, err = s.LoudnessGainTag()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if ok {
+			s.AudioStream().SetGain(math.Pow(10, gain/20))
+		}
+	}
+	if len(v.audioTracks) > 0 && audioOwner.AudioStream() == nil {
+		// Play the first additional audio track when the streams have no audio.
+		v.streams = append(v.streams, v.audioTracks[0])
+	}
+
+	if len(v.audioTracks) > 0 {
+		v.setAudioTrack(v.audioTracks[0])
+		audioStream := v.audioStream
+		for _, s := range v.audioTracks {
+			var src io.ReadSeeker = s.AudioStream()
+			if sf := s.AudioStream().SamplingFrequency(); sf != audioStream.SamplingFrequency() {
+				if options.OnWarning != nil {
+					options.OnWarning(fmt.Errorf("webmplayer: the audio track has a different sampling frequency (%d) from the first audio track (%d); the track is resampled", sf, audioStream.SamplingFrequency()))
+				}
+				src = newSampleRateStream(src, sf, audioStream.SamplingFrequency(), options.ResampleQuality, 0)
+			}
+			v.audioSources = append(v.audioSources, src)
+		}
+
+		sf := audioStream.SamplingFrequency()
+		crossfade := options.SeekCrossfade
+		if crossfade == 0 {
+			crossfade = 5 * time.Millisecond
+		}
+		chunk := int(int64(options.AudioChunkSize) * int64(sf) / int64(time.Second))
+		v.rateStream = newRateStream(audioStream, sf, options.ResampleQuality, max(crossfade, 0), chunk)
+		var src io.ReadSeeker = v.rateStream
+		ctx := options.AudioContext
+		if ctx == nil {
+			ctx = audio.CurrentContext()
+		}
+		switch {
+		case ctx == nil:
+			ctx = audio.NewContext(sf)
+			v.audioContextSetup = AudioContextCreated
+		case ctx.SampleRate() == sf:
+			v.audioContextSetup = AudioContextReused
+		default:
+			src = newSampleRateStream(v.rateStream, sf, ctx.SampleRate(), options.ResampleQuality, chunk)
+			v.audioContextSetup = AudioContextResampled
+		}
+		v.panStream = newPanStream(src)
+		v.audioTap = &audioTap{src: v.panStream}
+		p, err := ctx.NewPlayerF32(v.audioTap)
 		if err != nil {
 			return nil, err
 		}
+		if options.AudioBufferSize > 0 {
+			p.SetBufferSize(options.AudioBufferSize)
+		}
 		p.Play()
 		v.audioPlayer = p
+		v.stopAudioAtEnd = options.StopAudioAtEnd
+
+		v.audioLatency = options.AudioLatency
+		if v.audioLatency == 0 {
+			v.audioLatency = deviceLatency(ctx.SampleRate())
+		}
+		v.audioLatency = max(v.audioLatency, 0)
 	}
 	return v, nil
 }
 
+// AudioContextSetup represents how a player set up the audio context.
+type AudioContextSetup int
+
+const (
+	// AudioContextNone means that the player has no audio, and doesn't use an audio context.
+	AudioContextNone AudioContextSetup = iota
+
+	// AudioContextCreated means that the player created an audio context at the audio's sampling frequency, as there was no context.
+	AudioContextCreated
+
+	// AudioContextReused means that the player used the given or existing audio context at the same sampling frequency as the audio.
+	AudioContextReused
+
+	// AudioContextResampled means that the player used the given or existing audio context at a different sampling frequency,
+	// and resamples the audio to the context's sampling frequency.
+	AudioContextResampled
+)
+
+// AudioContextSetup returns how the player set up the audio context.
+//
+// A player uses the existing audio context if any, as only one context can exist.
+// This is useful to verify that a context created by the application is used as intended.
+func (p *Player) AudioContextSetup() AudioContextSetup {
+	return p.audioContextSetup
+}
+
+// AudioLatency returns the output latency of the audio device compensated by the player, or 0 if there is no audio.
+//
+// The latency is PlayerOptions.AudioLatency, or an estimate if it is not specified.
+// Position already includes the compensation. An application syncing external events with the audio,
+// like a rhythm game judging the user's input, can use this to calibrate its timing.
+func (p *Player) AudioLatency() time.Duration {
+	return p.audioLatency
+}
+
+// VideoSize returns the display size of the video.
+//
+// The display size can differ from the size of the encoded frames, e.g. for anamorphic videos.
+// The width and the height are swapped for a video rotated by 90 or 270 degrees, unless PlayerOptions.IgnoreOrientation is true.
+// Draw draws a frame in the display size.
 func (p *Player) VideoSize() (int, int) {
+	if p.rotate && (p.orientation == 90 || p.orientation == 270) {
+		return p.height, p.width
+	}
 	return p.width, p.height
 }
 
+// DecodedSize returns the size of the current decoded frame without the cropped edges, or zeros if no frame is decoded yet.
+//
+// As opposed to VideoSize, which is taken from the container's metadata, DecodedSize reflects the actual frames.
+// DecodedSize is the size of the image returned by CurrentFrame, and is neither scaled to the display size nor rotated.
+// The size can change during the playback, e.g. for a VP9 stream with resolution switching.
+func (p *Player) DecodedSize() (int, int) {
+	if p.videoStream == nil {
+		return 0, 0
+	}
+	size := p.videoStream.DecodedSize()
+	return size.X, size.Y
+}
+
+// SetDecodedSizeChangeCallback sets a function called when DecodedSize changes, including when the first frame is decoded.
+// The function is called in Update.
+//
+// If f is nil, the callback is removed.
+func (p *Player) SetDecodedSizeChangeCallback(f func(width, height int)) {
+	p.decodedSizeCallback = f
+}
+
+// Orientation returns the clockwise rotation in degrees to display the video upright: 0, 90, 180 or 270.
+// Videos recorded on phones are often encoded sideways with the rotation in the metadata.
+//
+// Draw applies the rotation unless PlayerOptions.IgnoreOrientation is true.
+// The other functions like CurrentFrame, Screenshot, RenderTo and DrawTriangles don't.
+func (p *Player) Orientation() int {
+	return p.orientation
+}
+
+// videoOrientation returns the clockwise rotation in degrees to display the video track, rounded to a multiple of 90.
+//
+// The rotation is taken from ProjectionPoseRoll, or the ROTATE tag for the track, which is a clockwise rotation like the rotate metadata of MP4.
+func videoOrientation(meta *webm.WebM, track *webm.TrackEntry) (int, error) {
+	var degrees float64
+	if roll := track.Video.Projection.ProjectionPoseRoll; roll != 0 {
+		// ProjectionPoseRoll is counter-clockwise.
+		degrees = -roll
+	} else if v, ok := meta.FindTag(track.TrackUID, "ROTATE"); ok {
+		d, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("webmplayer: invalid ROTATE tag: %q", v)
+		}
+		degrees = d
+	}
+	if math.IsNaN(degrees) || math.IsInf(degrees, 0) {
+		return 0, nil
+	}
+	d := int(math.Round(degrees/90)) * 90 % 360
+	if d < 0 {
+		d += 360
+	}
+	return d, nil
+}
+
+// VideoDuration returns the duration of the video stream, or 0 if there is no video.
 func (p *Player) VideoDuration() time.Duration {
 	return p.videoDuration
 }
 
+// VideoCodecID returns the codec ID of the video track like "V_VP9", or an empty string if there is no video.
 func (p *Player) VideoCodecID() string {
 	return p.videoCodecID
 }
 
+// AudioChannels returns the number of the channels of the current audio track, or 0 if there is no audio.
+// A track with more than two channels, like 5.1 or 7.1, is mixed down to stereo to play.
 func (p *Player) AudioChannels() int {
 	if p.audioStream == nil {
 		return 0
@@ -105,6 +702,7 @@ func (p *Player) AudioChannels() int {
 	return p.audioStream.Channels()
 }
 
+// AudioSamplingFrequency returns the sampling frequency of the audio, or 0 if there is no audio.
 func (p *Player) AudioSamplingFrequency() int {
 	if p.audioStream == nil {
 		return 0
@@ -112,52 +710,886 @@ func (p *Player) AudioSamplingFrequency() int {
 	return p.audioStream.SamplingFrequency()
 }
 
+// AudioDuration returns the duration of the current audio track's stream, or 0 if there is no audio.
 func (p *Player) AudioDuration() time.Duration {
 	return p.audioDuration
 }
 
+// AudioCodecID returns the codec ID of the current audio track like "A_OPUS", or an empty string if there is no audio.
 func (p *Player) AudioCodecID() string {
 	return p.audioCodecID
 }
 
+// SetLoopCount sets how many times the player plays the streams again after reaching the end.
+// A negative count makes the player loop forever.
+func (p *Player) SetLoopCount(count int) {
+	for _, s := range p.streams {
+		s.SetLoopCount(count)
+	}
+	for _, s := range p.audioTracks {
+		s.SetLoopCount(count)
+	}
+}
+
+// AudioTrackCount returns the number of the audio tracks.
+func (p *Player) AudioTrackCount() int {
+	return len(p.audioTracks)
+}
+
+// AudioTrack returns the index of the current audio track.
+func (p *Player) AudioTrack() int {
+	return p.audioTrack
+}
+
+// SetAudioTrack switches the audio track to the given index.
+//
+// The new audio track starts exactly from the position where the current audio track's output stops, so both are aligned sample-accurately.
+// Switching to or from the audio track of the video stream seeks the video stream too, which might cause a short stutter.
+func (p *Player) SetAudioTrack(index int) error {
+	if index < 0 || index >= len(p.audioTracks) {
+		return fmt.Errorf("webmplayer: audio track index out of range: %d", index)
+	}
+	if index == p.audioTrack {
+		return nil
+	}
+
+	prev := p.audioTracks[p.audioTrack]
+	next := p.audioTracks[index]
+
+	// The audio of a stream with video must not be demuxed while it is not used, not to block the video.
+	// An audio-only stream just stops demuxing when its buffer is full.
+	if prev.VideoStream() != nil {
+		prev.SetAudioEnabled(false)
+	}
+	next.SetAudioEnabled(true)
+
+	p.rateStream.SetSource(p.audioSources[index], func(position time.Duration) {
+		next.Seek(position)
+	})
+
+	p.streams = p.streams[:0]
+	if p.videoStream != nil {
+		p.streams = append(p.streams, p.videoOwner)
+	}
+	if next != p.videoOwner {
+		p.streams = append(p.streams, next)
+	}
+
+	p.audioTrack = index
+	p.setAudioTrack(next)
+	return nil
+}
+
+func (p *Player) setAudioTrack(s *stream) {
+	p.audioStream = s.AudioStream()
+	p.audioCodecID = s.Meta().FindFirstAudioTrack().CodecID
+	p.audioDuration = s.Meta().GetDuration()
+}
+
+// IsFinished reports whether all the streams have been played to the end.
+func (p *Player) IsFinished() bool {
+	if p.videoStream != nil && !p.videoStream.IsFinished() {
+		return false
+	}
+	if p.audioStream != nil && !p.audioStream.IsFinished() {
+		return false
+	}
+	if p.stopAudioAtEnd && p.audioPlayer != nil && !p.paused && p.audioPlayer.IsPlaying() {
+		// The audio is still being played until the audio player stops.
+		return false
+	}
+	return true
+}
+
+// BuildIndex scans the streams to index the keyframes, if they have no cue points, e.g. files recorded by streaming software.
+// The index makes Seek and Scrub fast, as they can start decoding from the keyframe before the position instead of the start.
+//
+// Without BuildIndex, the keyframes are indexed lazily as the streams are played,
+// but Seek without Exact doesn't snap to them as the index is incomplete.
+//
+// A WebM stream without cue points must implement io.ReaderAt, like *os.File and *bytes.Reader, to be read concurrently with the playback.
+// The other streams are not scanned.
+//
+// BuildIndex blocks until the scan finishes or ctx is done, so BuildIndex can be called in a separate goroutine.
+func (p *Player) BuildIndex(ctx context.Context) error {
+	// p.streams is not used as it can be changed by SetAudioTrack concurrently.
+	if err := p.videoOwner.BuildIndex(ctx); err != nil {
+		return err
+	}
+	for _, s := range p.audioTracks {
+		if s == p.videoOwner {
+			continue
+		}
+		if err := s.BuildIndex(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SeekOptions represents options for Seek.
+type SeekOptions struct {
+	// Exact specifies whether the player seeks to the exact position.
+	//
+	// If Exact is false, the player seeks to the keyframe at or before the position, which is fast.
+	// If Exact is true, the player decodes from the keyframe and discards frames and samples until the position.
+	// If the stream has no cue points and BuildIndex has not been completed, the player always seeks to the exact position.
+	//
+	// The default (zero) value is false.
+	Exact bool
+}
+
+// Seek seeks to the given position.
+//
+// If options is nil, the default values are used.
+func (p *Player) Seek(position time.Duration, options *SeekOptions) error {
+	target := position
+	if options == nil || !options.Exact {
+		if pos, ok := p.streams[0].KeyframeBefore(position); ok {
+			target = pos
+		}
+	}
+
+	return p.seek(target)
+}
+
+func (p *Player) seek(target time.Duration) error {
+	if p.IsLive() && target < p.position() {
+		return fmt.Errorf("webmplayer: a live stream can't be sought backward")
+	}
+
+	p.updateWatchTime(target)
+	p.ended = false
+	p.scrubbing = false
+
+	for _, s := range p.streams {
+		s.Seek(target)
+	}
+
+	if p.audioPlayer != nil {
+		if p.audioPlayer.IsPlaying() {
+			p.rateStream.PrepareCrossfade(p.audioPlayer.Position())
+		}
+		if err := p.audioPlayer.SetPosition(target); err != nil {
+			return err
+		}
+		p.audioSeekPosition = target
+		if p.stopAudioAtEnd && !p.paused {
+			// The audio player might have stopped at the end.
+			p.audioPlayer.Play()
+		}
+	} else {
+		p.startTime = time.Now()
+		p.startPosition = target
+	}
+	return nil
+}
+
+// ScrubOptions represents options for Scrub.
+type ScrubOptions struct {
+	// Duration is the duration of the audio snippet.
+	//
+	// The default (zero) value is 80 milliseconds.
+	Duration time.Duration
+
+	// Volume is the volume of the audio snippet.
+	//
+	// The default (zero) value is 0.5.
+	Volume float64
+}
+
+// Scrub seeks to the exact given position, plays a short audio snippet from there, and holds the position.
+// Scrub is useful to let users hear the audio while dragging a seek bar, like video editors.
+//
+// The position is held until the next Seek, which resumes the playback.
+//
+// If options is nil, the default values are used.
+func (p *Player) Scrub(position time.Duration, options *ScrubOptions) error {
+	if options == nil {
+		options = &ScrubOptions{}
+	}
+	d := options.Duration
+	if d == 0 {
+		d = 80 * time.Millisecond
+	}
+	volume := options.Volume
+	if volume == 0 {
+		volume = 0.5
+	}
+
+	if err := p.seek(position); err != nil {
+		return err
+	}
+	if p.rateStream != nil {
+		p.rateStream.Scrub(d, volume)
+	}
+	p.scrubbing = true
+	return nil
+}
+
+// PlaybackRateOptions represents options for SetPlaybackRate.
+type PlaybackRateOptions struct {
+	// PreservePitch specifies whether the audio keeps its pitch at a rate other than 1.
+	//
+	// If PreservePitch is true, the audio is time-stretched, which keeps voices natural.
+	// If PreservePitch is false, the audio is resampled, and its pitch changes with the rate like a tape.
+	//
+	// The default (zero) value is false.
+	PreservePitch bool
+}
+
+// SetPlaybackRate sets the playback rate. 1 is the normal speed.
+//
+// If options is nil, the default values are used.
+func (p *Player) SetPlaybackRate(rate float64, options *PlaybackRateOptions) error {
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return fmt.Errorf("webmplayer: rate must be positive and finite: %f", rate)
+	}
+	if options == nil {
+		options = &PlaybackRateOptions{}
+	}
+
+	if p.rateStream == nil {
+		p.startPosition = p.position()
+		p.startTime = time.Now()
+	} else {
+		p.rateStream.SetRate(rate, options.PreservePitch)
+	}
+	p.playbackRate = rate
+
+	if p.videoStream != nil {
+		p.videoStream.SetRate(rate)
+	}
+	return nil
+}
+
+// PlaybackRate returns the current playback rate.
+func (p *Player) PlaybackRate() float64 {
+	return p.playbackRate
+}
+
+// Pause pauses the playback.
+//
+// The audio player is paused, and the clock stops at the current position, so no video frame is shown or dropped until Play.
+// Seek and Scrub while paused keep the player paused, and show the frame at the new position.
+func (p *Player) Pause() {
+	if p.paused {
+		return
+	}
+	pos := p.position()
+	if p.audioPlayer != nil {
+		p.audioPlayer.Pause()
+	}
+	p.startPosition = pos
+	p.paused = true
+	if p.videoStream != nil {
+		p.videoStream.SetPaused(true, pos)
+	}
+}
+
+// Play resumes the playback paused by Pause.
+//
+// The clock restarts from the paused position, so the frames after it are shown in time without skipping.
+func (p *Player) Play() {
+	if !p.paused {
+		return
+	}
+	p.paused = false
+	p.startTime = time.Now()
+	if p.audioPlayer != nil {
+		p.audioPlayer.Play()
+	}
+	if p.videoStream != nil {
+		p.videoStream.SetPaused(false, p.position())
+	}
+}
+
+// IsPaused reports whether the playback is paused by Pause.
+func (p *Player) IsPaused() bool {
+	return p.paused
+}
+
+// Preload decodes and buffers the first d of audio and the first video frame.
+// The playback is paused while preloading, and resumed after that unless Pause has been called.
+//
+// Preload is useful to avoid stutters at the beginning of the playback on slow media.
+// Call Preload just after creating the player.
+//
+// The amount of preloaded audio might be less than d when the internal buffers are full.
+func (p *Player) Preload(ctx context.Context, d time.Duration) error {
+	if p.audioPlayer != nil && !p.paused {
+		p.audioPlayer.Pause()
+		defer p.audioPlayer.Play()
+	}
+
+	if p.audioStream != nil {
+		stalled := func() bool {
+			for _, s := range p.streams {
+				if s.audioStream == p.audioStream && s.videoStream != nil {
+					return s.videoStream.IsBufferFull()
+				}
+			}
+			return false
+		}
+		if err := p.audioStream.Preload(ctx, d, stalled); err != nil {
+			return err
+		}
+	}
+
+	if p.videoStream != nil {
+		if err := p.videoStream.WaitForFirstFrame(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DominantColor returns the dominant color of the current video frame.
+// The color is useful to tint the surrounding UI, like ambient lighting.
+//
+// DominantColor returns false if there is no video or no frame is decoded yet.
+func (p *Player) DominantColor() (color.RGBA, bool) {
+	if p.videoStream == nil {
+		return color.RGBA{}, false
+	}
+	return p.videoStream.DominantColor()
+}
+
+// LumaHistogram returns the luma histogram and the exposure of the current video frame.
+// The histogram is computed only when LumaHistogram is called.
+//
+// The returned value must not be modified.
+//
+// LumaHistogram returns nil if there is no video or no frame is decoded yet.
+func (p *Player) LumaHistogram() *LumaHistogram {
+	if p.videoStream == nil {
+		return nil
+	}
+	return p.videoStream.LumaHistogram()
+}
+
+// SceneChangeEvent represents a scene change in the video.
+type SceneChangeEvent struct {
+	// Position is the position of the first frame of the new scene.
+	Position time.Duration
+
+	// Difference is the mean absolute luma difference between the frames in [0, 1].
+	Difference float64
+}
+
+// SceneChangeOptions represents options for SetSceneChangeCallback.
+type SceneChangeOptions struct {
+	// Threshold is the minimum difference between consecutive frames to be treated as a scene change, in (0, 1].
+	// The difference is the mean absolute difference of downscaled luma values.
+	//
+	// The default (zero) value is 0.15.
+	Threshold float64
+}
+
+// SetSceneChangeCallback sets a function called when a scene change is detected.
+// The function is called in Update.
+//
+// Seeking is not treated as a scene change.
+// If f is nil, the detection is disabled.
+//
+// If options is nil, the default values are used.
+func (p *Player) SetSceneChangeCallback(f func(event *SceneChangeEvent), options *SceneChangeOptions) {
+	p.sceneChangeCallback = f
+	if p.videoStream == nil {
+		return
+	}
+	if f == nil {
+		p.videoStream.SetSceneChangeThreshold(0)
+		return
+	}
+	threshold := 0.15
+	if options != nil && options.Threshold > 0 {
+		threshold = options.Threshold
+	}
+	p.videoStream.SetSceneChangeThreshold(threshold)
+}
+
+// SetVideoFrameCallback sets a function called with each decoded frame to show and its presentation timestamp.
+//
+// f is called in the decoding goroutine, not in Update, so f should return quickly not to delay the video.
+// frame must not be modified, and is valid only during the call. The alpha channel is not included even if the video has it.
+// If f is nil, the callback is removed.
+func (p *Player) SetVideoFrameCallback(f func(frame *image.YCbCr, pts time.Duration)) {
+	if p.videoStream == nil {
+		return
+	}
+	p.videoStream.SetFrameCallback(f)
+}
+
+// SetAudioTap sets a function called with the samples handed to the audio player, e.g. to visualize the waveform or the spectrum.
+//
+// samples is interleaved stereo float32 at the audio context's sample rate, after the playback rate is applied.
+// As the audio player buffers the samples before they are heard, samples are ahead of Position by about the buffer size.
+// f is called in the audio goroutine, not in Update, so f should return quickly not to cause audio glitches.
+// samples must not be modified, and is valid only during the call.
+// If f is nil, the tap is removed. SetAudioTap does nothing if there is no audio.
+func (p *Player) SetAudioTap(f func(samples []float32)) {
+	if p.audioTap == nil {
+		return
+	}
+	p.audioTap.SetCallback(f)
+}
+
+// SetPan sets the stereo balance of the audio, e.g. to position a video in a game world.
+//
+// pan is in [-1, 1]. -1 is the left only, 0 is the center and 1 is the right only.
+// A negative pan attenuates the right channel and a positive pan attenuates the left channel linearly, without changing the other channel.
+// SetPan does nothing if there is no audio.
+func (p *Player) SetPan(pan float64) {
+	pan = min(max(pan, -1), 1)
+	p.SetChannelGains(min(1, 1-pan), min(1, 1+pan))
+}
+
+// SetChannelGains sets the linear gains of the left and the right channels of the audio. The default gains are 1.
+//
+// The gains are applied in addition to the audio player's volume and PlayerOptions.AudioGains.
+// SetChannelGains does nothing if there is no audio.
+func (p *Player) SetChannelGains(left, right float64) {
+	if p.panStream == nil {
+		return
+	}
+	p.panStream.SetGains(max(left, 0), max(right, 0))
+}
+
+// ChannelGains returns the linear gains of the left and the right channels of the audio.
+// ChannelGains returns 1 and 1 if there is no audio.
+func (p *Player) ChannelGains() (left, right float64) {
+	if p.panStream == nil {
+		return 1, 1
+	}
+	return p.panStream.Gains()
+}
+
+// SetFrameChangeCallback sets a function called when the frame to draw changes, with the frame's presentation timestamp.
+// This is useful to synchronize something with the presented frames, like subtitles, without polling the position.
+//
+// f is called in Update. If multiple frames are shown between two Update calls, f is called only for the last one, which is the frame to draw.
+// A frame identical to the previous one is treated as a new frame too.
+// If f is nil, the callback is removed.
+func (p *Player) SetFrameChangeCallback(f func(pts time.Duration)) {
+	p.frameChangeCallback = f
+}
+
+// Position returns the current playing position.
+//
+// For a stream without video, Position is based on the decoded audio samples, and stops at the end of the audio.
+func (p *Player) Position() time.Duration {
+	return p.position()
+}
+
+// Update updates the player's state. Update must be called every tick, e.g. in ebiten.Game's Update.
+//
+// Update returns an error if decoding has failed.
 func (p *Player) Update() error {
-	if err := p.videoStream.Update(p.audioPlayer.Position()); err != nil {
+	if err := p.updateLiveLatency(); err != nil {
 		return err
 	}
+
+	ended := p.ended
+	p.updateEnd()
+	if p.ended && !ended && p.videoStream != nil {
+		// The last frame stays visible, but the buffers to convert frames are not needed until seeking.
+		p.videoStream.ReleaseConversionResources()
+	}
+
+	if p.videoStream == nil {
+		return nil
+	}
+	if err := p.videoStream.Update(p.position()); err != nil {
+		return err
+	}
+
+	if pts, n := p.videoStream.ShownFrame(); n != p.shownFrames {
+		p.shownFrames = n
+		if p.frameChangeCallback != nil {
+			p.frameChangeCallback(pts)
+		}
+	}
+
+	if size := p.videoStream.DecodedSize(); size != p.decodedSize {
+		p.decodedSize = size
+		if p.decodedSizeCallback != nil {
+			p.decodedSizeCallback(size.X, size.Y)
+		}
+	}
+
+	if p.sceneChangeCallback != nil {
+		p.sceneChangeEvents = p.videoStream.AppendSceneChangeEvents(p.sceneChangeEvents[:0])
+		for i := range p.sceneChangeEvents {
+			p.sceneChangeCallback(&p.sceneChangeEvents[i])
+		}
+	}
 	return nil
 }
 
-type PlayerDrawOptions struct {
-	GeoM       ebiten.GeoM
+// IsLive reports whether the stream is a live stream, like a WebM stream from a live encoder with an unknown size.
+//
+// A live stream has no duration, and its position starts at 0 at the first packet received.
+// A live stream can be sought only forward, which skips to the first keyframe at or after the position.
+func (p *Player) IsLive() bool {
+	return p.videoOwner.IsLive()
+}
+
+// updateLiveLatency skips to the live edge if the playback of a live stream falls behind it by more than MaxLiveLatency.
+func (p *Player) updateLiveLatency() error {
+	if p.maxLiveLatency <= 0 || p.paused || p.scrubbing || !p.IsLive() || p.videoOwner.IsLiveSkipping() {
+		return nil
+	}
+	edge, ok := p.videoOwner.LiveEdge()
+	if !ok || edge-p.position() <= p.maxLiveLatency {
+		return nil
+	}
+	// Leave a half of the latency as a margin for the jitter of the network.
+	return p.seek(edge - p.maxLiveLatency/2)
+}
+
+func (p *Player) position() time.Duration {
+	if p.audioPlayer != nil {
+		// The audio at the audio player's position is just sent to the device, and is heard after the latency.
+		pos := p.rateStream.MediaPosition(max(p.audioPlayer.Position()-p.audioLatency, p.audioSeekPosition))
+		if p.videoStream == nil && p.audioStream.IsFinished() {
+			// The audio player keeps playing silence after the end of the stream.
+			// Without video, nothing defines the position there, so stop at the last decoded sample.
+			pos = min(pos, p.audioStream.DecodedEnd())
+		}
+		return pos
+	}
+	if p.scrubbing || p.paused {
+		return p.startPosition
+	}
+	if p.startTime.IsZero() {
+		p.startTime = time.Now()
+	}
+	return p.startPosition + time.Duration(float64(time.Since(p.startTime))*p.playbackRate)
+}
+
+// CurrentFrame returns the current frame, or nil if there is no frame yet.
+//
+// The returned image is owned by the player, and must not be modified.
+// Its content is updated to a later frame at the next CurrentFrame or Draw call.
+// The image is in the size of the encoded frame without the cropped edges, which might differ from VideoSize for anamorphic videos.
+func (p *Player) CurrentFrame() *ebiten.Image {
+	if p.videoStream == nil {
+		return nil
+	}
+	return p.videoStream.Image()
+}
+
+// Screenshot returns a copy of the current frame as an *image.RGBA, which is useful for thumbnails and bug reports.
+//
+// The image is converted on CPU, so Screenshot can be called anytime, e.g. outside of the game loop.
+// The image is in the size of the encoded frame without the cropped edges.
+//
+// Screenshot returns an error if there is no video or no frame is decoded yet.
+func (p *Player) Screenshot() (image.Image, error) {
+	if p.videoStream == nil {
+		return nil, fmt.Errorf("webmplayer: no video to take a screenshot")
+	}
+	img := p.videoStream.Screenshot()
+	if img == nil {
+		return nil, fmt.Errorf("webmplayer: no video frame is decoded yet")
+	}
+	return img, nil
+}
+
+// ReleaseVideoResources deallocates the images and the buffers for the video, e.g. for a paused player in the background.
+// The current frame is kept on the CPU side, and the images are allocated again when the player draws next time.
+//
+// The buffers to convert frames are released automatically when the player finishes, and all the resources are released by Close.
+func (p *Player) ReleaseVideoResources() {
+	if p.videoStream == nil {
+		return
+	}
+	p.videoStream.ReleaseResources()
+}
+
+// Close stops the playback, and releases the resources like the goroutines, the audio player and the video images.
+// With the webmplayer_allocstats build tag, Close logs the allocations of each subsystem, e.g. demux and video decode, since the process started.
+// The player must not be used after Close.
+func (p *Player) Close() error {
+	var err error
+	if p.audioPlayer != nil {
+		err = p.audioPlayer.Close()
+	}
+	for _, s := range p.streams {
+		s.Close()
+	}
+	for _, s := range p.audioTracks {
+		s.Close()
+	}
+	if p.videoStream != nil {
+		p.videoStream.Close()
+	}
+	if p.httpReader != nil {
+		p.httpReader.Close()
+	}
+	if allocstats.Enabled {
+		log.Print(allocstats.Report())
+	}
+	return err
+}
+
+// DrawOptions represents options for Draw.
+type DrawOptions struct {
+	// GeoM is the geometry matrix applied after the frame is scaled to the display size.
+	GeoM ebiten.GeoM
+
 	ColorScale ebiten.ColorScale
 	Blend      ebiten.Blend
+
+	// Filter is the filter to scale the frame. Filter is ignored when Shader is specified.
+	//
+	// The default (zero) value is FilterLinear.
+	Filter Filter
+
+	// Shader is a shader to draw the frame, which is useful for post effects like CRT or color grading.
+	// The frame is passed to the shader as the first source image, in premultiplied RGBA.
+	//
+	// The default (zero) value is nil, which draws the frame as it is.
+	Shader *ebiten.Shader
+
+	// Uniforms is the uniform variables for Shader.
+	//
+	// The default (zero) value is nil.
+	Uniforms map[string]any
+
+	// Placeholder is the color to fill the video's area with until the first frame is decoded, e.g. color.Black,
+	// so that the scene under the video doesn't flash.
+	// The area is transformed in the same way as the frame, and ColorScale and Blend are applied.
+	//
+	// The default (zero) value is nil, which draws nothing until the first frame.
+	Placeholder color.Color
+
+	// Poster is an image to draw in the video's area until the first frame is decoded. Poster is scaled to the display size,
+	// and is drawn over Placeholder.
+	//
+	// The default (zero) value is nil.
+	Poster *ebiten.Image
+}
+
+// whiteImage is a white image to fill areas. The center pixel is used to avoid sampling the edges.
+var (
+	whiteImage     *ebiten.Image
+	whiteImageOnce sync.Once
+)
+
+func whiteSubImage() *ebiten.Image {
+	whiteImageOnce.Do(func() {
+		whiteImage = ebiten.NewImage(3, 3)
+		whiteImage.Fill(color.White)
+	})
+	return whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
 }
 
-func (p *Player) Draw(screen *ebiten.Image, options *PlayerDrawOptions) {
+// Filter represents a filter to scale frames.
+type Filter int
+
+const (
+	// FilterLinear is the linear filter.
+	FilterLinear Filter = iota
+
+	// FilterNearest is the nearest-neighbor filter, which keeps the pixels sharp, e.g. for pixel art.
+	FilterNearest
+)
+
+// PlayerDrawOptions represents options for Draw.
+//
+// Deprecated: as of v1. Use DrawOptions instead.
+type PlayerDrawOptions = DrawOptions
+
+// Draw draws the current frame on screen in the display size.
+// The frame is rotated upright by Orientation, unless PlayerOptions.IgnoreOrientation is true.
+//
+// If options is nil, the default values are used.
+func (p *Player) Draw(screen *ebiten.Image, options *DrawOptions) {
 	if p.videoStream == nil {
+		if p.diagnostics {
+			p.warn(diagnosticNoVideo, "Draw is called, but the stream has no video and VideoSize is zero")
+		}
 		return
 	}
+	var drawn bool
 	p.videoStream.Draw(func(image *ebiten.Image) {
+		drawn = true
+
+		geoM := p.drawGeoM(image.Bounds(), options)
+		var colorScale ebiten.ColorScale
+		var blend ebiten.Blend
+		if options != nil {
+			colorScale = options.ColorScale
+			blend = options.Blend
+		}
+		if p.diagnostics {
+			p.diagnoseDraw(screen, image, geoM, colorScale)
+		}
+
+		if options != nil && options.Shader != nil {
+			op := &ebiten.DrawRectShaderOptions{}
+			op.GeoM = geoM
+			op.ColorScale = colorScale
+			op.Blend = blend
+			op.Uniforms = options.Uniforms
+			op.Images[0] = image
+			b := image.Bounds()
+			screen.DrawRectShader(b.Dx(), b.Dy(), options.Shader, op)
+			return
+		}
+
 		op := &ebiten.DrawImageOptions{}
+		op.GeoM = geoM
+		op.ColorScale = colorScale
+		op.Blend = blend
 		op.Filter = ebiten.FilterLinear
-		if options != nil {
-			op.GeoM = options.GeoM
-			op.ColorScale = options.ColorScale
-			op.Blend = options.Blend
+		if options != nil && options.Filter == FilterNearest {
+			op.Filter = ebiten.FilterNearest
 		}
 		screen.DrawImage(image, op)
 	})
+	if drawn {
+		return
+	}
+	if options != nil && (options.Placeholder != nil || options.Poster != nil) {
+		p.drawPlaceholder(screen, options)
+		return
+	}
+	if p.diagnostics {
+		p.warn(diagnosticNoFrame, "Draw is called before the first frame is decoded, and nothing is drawn; Preload can wait for the first frame")
+	}
+}
+
+// drawGeoM returns the geometry matrix to draw an image with the given bounds in the display size.
+func (p *Player) drawGeoM(bounds image.Rectangle, options *DrawOptions) ebiten.GeoM {
+	var geoM ebiten.GeoM
+	// Scale the frame to the display size for a non-square pixel aspect ratio.
+	if p.width > 0 && p.height > 0 && (bounds.Dx() != p.width || bounds.Dy() != p.height) {
+		geoM.Scale(float64(p.width)/float64(bounds.Dx()), float64(p.height)/float64(bounds.Dy()))
+	}
+	if p.rotate && p.orientation != 0 {
+		// Rotate around the origin, and move the rotated frame back to the upper-left corner.
+		geoM.Rotate(float64(p.orientation) * math.Pi / 180)
+		switch p.orientation {
+		case 90:
+			geoM.Translate(float64(p.height), 0)
+		case 180:
+			geoM.Translate(float64(p.width), float64(p.height))
+		case 270:
+			geoM.Translate(0, float64(p.width))
+		}
+	}
+	if options != nil {
+		geoM.Concat(options.GeoM)
+	}
+	return geoM
+}
+
+// drawPlaceholder draws the placeholder color and the poster of options in the video's area.
+func (p *Player) drawPlaceholder(screen *ebiten.Image, options *DrawOptions) {
+	if options.Placeholder != nil {
+		img := whiteSubImage()
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM = p.drawGeoM(img.Bounds(), options)
+		op.ColorScale.ScaleWithColor(options.Placeholder)
+		op.ColorScale.ScaleWithColorScale(options.ColorScale)
+		op.Blend = options.Blend
+		screen.DrawImage(img, op)
+	}
+	if options.Poster != nil {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM = p.drawGeoM(options.Poster.Bounds(), options)
+		op.ColorScale = options.ColorScale
+		op.Blend = options.Blend
+		op.Filter = ebiten.FilterLinear
+		if options.Filter == FilterNearest {
+			op.Filter = ebiten.FilterNearest
+		}
+		screen.DrawImage(options.Poster, op)
+	}
+}
+
+// RenderTo copies the current frame to dst pixel by pixel at the upper-left corner of dst, for pixel-exact compositing.
+//
+// The frame is in the size of the encoded frame without the cropped edges, and the pixel aspect ratio is not applied.
+// The pixels of dst in the area are replaced with the frame's premultiplied RGBA values, and the other pixels are not changed.
+//
+// RenderTo does nothing if there is no frame yet.
+func (p *Player) RenderTo(dst *ebiten.Image) {
+	if p.videoStream == nil {
+		return
+	}
+	p.videoStream.Draw(func(image *ebiten.Image) {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(dst.Bounds().Min.X), float64(dst.Bounds().Min.Y))
+		op.Blend = ebiten.BlendCopy
+		dst.DrawImage(image, op)
+	})
+}
+
+// DrawTrianglesOptions represents options for DrawTriangles.
+type DrawTrianglesOptions struct {
+	ColorScaleMode ebiten.ColorScaleMode
+	Blend          ebiten.Blend
+	Address        ebiten.Address
+	AntiAlias      bool
+
+	// Filter is the filter to sample the frame.
+	//
+	// The default (zero) value is FilterLinear.
+	Filter Filter
+}
+
+// DrawTriangles draws triangles with the current frame as the texture, e.g. to map the video onto a billboard or a curved screen.
+//
+// SrcX and SrcY of the vertices are in the pixels of the frame from (0, 0), where the frame is in the size of the encoded frame without the cropped edges.
+// Note that the size might differ from VideoSize for anamorphic videos.
+// The vertices and the indices are not modified.
+//
+// DrawTriangles does nothing if there is no frame yet.
+//
+// If options is nil, the default values are used.
+func (p *Player) DrawTriangles(dst *ebiten.Image, vertices []ebiten.Vertex, indices []uint16, options *DrawTrianglesOptions) {
+	if p.videoStream == nil {
+		return
+	}
+	if options == nil {
+		options = &DrawTrianglesOptions{}
+	}
+	p.videoStream.Draw(func(image *ebiten.Image) {
+		// The frame is a sub-image, and source positions are in the coordinates of the whole image.
+		b := image.Bounds()
+		p.vertices = append(p.vertices[:0], vertices...)
+		for i := range p.vertices {
+			p.vertices[i].SrcX += float32(b.Min.X)
+			p.vertices[i].SrcY += float32(b.Min.Y)
+		}
+
+		op := &ebiten.DrawTrianglesOptions{}
+		op.ColorScaleMode = options.ColorScaleMode
+		op.Blend = options.Blend
+		op.Address = options.Address
+		op.AntiAlias = options.AntiAlias
+		op.Filter = ebiten.FilterLinear
+		if options.Filter == FilterNearest {
+			op.Filter = ebiten.FilterNearest
+		}
+		dst.DrawTriangles(p.vertices, indices, image, op)
+	})
 }
 
 // discoverStreams returns both Video and Audio streams if in separate inputs,
 // otherwise only the first stream would be returned (Video / Audio / Video + Audio).
-func discoverStreams(streams ...io.ReadSeeker) (*stream, *stream, error) {
+func discoverStreams(options *streamOptions, streams ...io.ReadSeeker) (*stream, *stream, error) {
 	if len(streams) == 0 {
 		return nil, nil, fmt.Errorf("webmplayer: no streams found")
 	}
 
 	if len(streams) == 1 {
-		stream, err := newStream(streams[0])
+		stream, err := newStream(streams[0], options)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -166,7 +1598,7 @@ func discoverStreams(streams ...io.ReadSeeker) (*stream, *stream, error) {
 
 	var stream1Video bool
 	var stream1Audio bool
-	stream1, err := newStream(streams[0])
+	stream1, err := newStream(streams[0], options)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -179,7 +1611,7 @@ func discoverStreams(streams ...io.ReadSeeker) (*stream, *stream, error) {
 
 	var stream2Video bool
 	var stream2Audio bool
-	stream2, err := newStream(streams[1])
+	stream2, err := newStream(streams[1], options)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,499 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// rateStream changes the playback rate of a stereo float32 stream.
+//
+// The positions of rateStream are in the output frames, which differ from the positions in the source (media) at a rate other than 1.
+// rateStream keeps anchors to convert an output position to a media position.
+type rateStream struct {
+	src               io.ReadSeeker
+	samplingFrequency int
+
+	rate          float64
+	preservePitch bool
+
+	// interp interpolates frames to resample them.
+	interp interpolator
+
+	// chunk is the number of frames processed at a time.
+	chunk int
+
+	// in is the source frames not consumed yet.
+	in []float32
+	// cursor is the read position in frames in in.
+	cursor float64
+
+	// out is the output frames not read yet.
+	out []float32
+
+	// srcEOF indicates that the source has ended.
+	srcEOF bool
+	// padding is the number of the silent frames padded at the end of in after the source ends.
+	padding int
+
+	// window, ola and prevSegment are used for time-stretching (WSOLA).
+	window []float32
+	ola    []float32
+	// prevSegment is the start position in frames in in of the last overlap-added segment, which is valid when hasPrev is true.
+	// prevSegment can be negative after trimming, as only the frames after prevSegment+hop are needed for the next search.
+	prevSegment int
+	hasPrev     bool
+	// tolerance is the range in frames to search a similar segment.
+	tolerance int
+
+	// scrubLength is the number of frames of the snippet while scrubbing, or 0 if not scrubbing.
+	scrubLength int
+	// scrubPos is the number of frames of the snippet already played.
+	scrubPos    int
+	scrubVolume float32
+
+	// crossfade is the number of frames to cross-fade at a seek, or 0 not to cross-fade.
+	crossfade int
+	// history is the last frames returned by Read, which end at outPos.
+	history []float32
+	// fadeOut is the frames after the audible position before a seek, which are faded out after the seek.
+	fadeOut []float32
+	// fadePos is the number of frames of fadeOut already mixed.
+	fadePos int
+
+	// outPos is the position in frames of the next frame read by Read.
+	outPos  int64
+	anchors []rateAnchor
+
+	buf []byte
+
+	m sync.Mutex
+}
+
+// rateAnchor represents a position from which a rate is applied.
+type rateAnchor struct {
+	out   int64
+	media float64
+	rate  float64
+}
+
+// maxRateAnchors is the maximum number of anchors kept.
+// Old anchors are still needed until the audio player's buffered data is played.
+const maxRateAnchors = 16
+
+// maxRateHistory is the maximum duration of the frames kept for a cross-fade.
+// This must cover the audio player's buffered data, which is already read but not audible yet.
+const maxRateHistory = 500 * time.Millisecond
+
+// newRateStream creates a rateStream. crossfade is the duration to cross-fade at a seek, or 0 not to cross-fade.
+// chunk is the number of frames processed at a time, or 0 for samplesPerBuffer.
+func newRateStream(src io.ReadSeeker, samplingFrequency int, quality ResampleQuality, crossfade time.Duration, chunk int) *rateStream {
+	if chunk <= 0 {
+		chunk = samplesPerBuffer
+	}
+	// A 40ms window with 50% overlap.
+	n := samplingFrequency * 40 / 1000 / 2 * 2
+	window := make([]float32, n)
+	for i := range window {
+		window[i] = float32(0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n)))
+	}
+	return &rateStream{
+		src:               src,
+		samplingFrequency: samplingFrequency,
+		rate:              1,
+		interp:            interpolator{quality: quality},
+		chunk:             chunk,
+		crossfade:         int(int64(crossfade) * int64(samplingFrequency) / int64(time.Second)),
+		window:            window,
+		ola:               make([]float32, 2*n),
+		tolerance:         samplingFrequency * 10 / 1000,
+		anchors: []rateAnchor{
+			{rate: 1},
+		},
+	}
+}
+
+// SetRate sets the playback rate.
+// The new rate is applied after the already generated frames.
+func (r *rateStream) SetRate(rate float64, preservePitch bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.rate == rate && r.preservePitch == preservePitch {
+		return
+	}
+
+	r.rate = rate
+	r.preservePitch = preservePitch
+	r.resetStretch()
+
+	if r.scrubLength > 0 {
+		// The position is held while scrubbing.
+		return
+	}
+
+	out := r.outPos + int64(len(r.out)/2)
+	r.anchors = append(r.anchors, rateAnchor{
+		out:   out,
+		media: r.mediaFrameAt(out),
+		rate:  rate,
+	})
+	if len(r.anchors) > maxRateAnchors {
+		r.anchors = r.anchors[len(r.anchors)-maxRateAnchors:]
+	}
+}
+
+// MediaPosition converts the given output position to the position in the source.
+func (r *rateStream) MediaPosition(position time.Duration) time.Duration {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	out := int64(position) * int64(r.samplingFrequency) / int64(time.Second)
+	return time.Duration(r.mediaFrameAt(out) * float64(time.Second) / float64(r.samplingFrequency))
+}
+
+func (r *rateStream) mediaFrameAt(out int64) float64 {
+	a := r.anchors[0]
+	for _, a1 := range r.anchors[1:] {
+		if a1.out > out {
+			break
+		}
+		a = a1
+	}
+	return a.media + float64(out-a.out)*a.rate
+}
+
+func (r *rateStream) Read(buf []byte) (int, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if len(buf) < 8 {
+		return 0, nil
+	}
+
+	dst := unsafe.Slice((*float32)(unsafe.Pointer(unsafe.SliceData(buf))), len(buf)/8*2)
+
+	if r.scrubLength > 0 && r.scrubPos >= r.scrubLength {
+		// The snippet has been played. Hold the position with silence.
+		clear(dst)
+		r.outPos += int64(len(dst) / 2)
+		return 4 * len(dst), nil
+	}
+
+	if len(r.out) == 0 {
+		if r.srcEOF && int(r.cursor) >= len(r.in)/2-r.padding {
+			return 0, io.EOF
+		}
+		if err := r.process(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.scrubLength > 0 {
+		dst = dst[:min(len(dst), 2*(r.scrubLength-r.scrubPos))]
+	}
+	n := copy(dst, r.out)
+	r.out = r.out[n:]
+	r.outPos += int64(n / 2)
+
+	if r.scrubLength > 0 {
+		r.applyScrubEnvelope(dst[:n])
+	}
+	r.mixFadeOut(dst[:n])
+
+	if r.crossfade > 0 {
+		r.history = append(r.history, dst[:n]...)
+		// Trim the history only occasionally not to copy the frames at every Read.
+		if limit := 2 * int(int64(maxRateHistory)*int64(r.samplingFrequency)/int64(time.Second)); len(r.history) > 2*limit {
+			r.history = append(r.history[:0], r.history[len(r.history)-limit:]...)
+		}
+	}
+	return 4 * n, nil
+}
+
+// PrepareCrossfade keeps the frames from the given audible output position to fade them out at the next Seek.
+// PrepareCrossfade should be called just before Seek while playing.
+func (r *rateStream) PrepareCrossfade(position time.Duration) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.fadeOut = r.fadeOut[:0]
+	r.fadePos = 0
+	if r.crossfade == 0 || r.scrubLength > 0 {
+		return
+	}
+
+	// The history ends at outPos, and the frames after the audible position are still in the audio player's buffer.
+	start := int(int64(position)*int64(r.samplingFrequency)/int64(time.Second) - (r.outPos - int64(len(r.history)/2)))
+	if start < 0 || start >= len(r.history)/2 {
+		return
+	}
+	n := min(r.crossfade, len(r.history)/2-start)
+	r.fadeOut = append(r.fadeOut, r.history[2*start:2*(start+n)]...)
+}
+
+// mixFadeOut mixes the frames before the last seek into frames with a linear cross-fade.
+func (r *rateStream) mixFadeOut(frames []float32) {
+	n := len(r.fadeOut) / 2
+	for i := 0; i < len(frames) && r.fadePos < n; i += 2 {
+		g := float32(r.fadePos) / float32(n)
+		frames[i] = frames[i]*g + r.fadeOut[2*r.fadePos]*(1-g)
+		frames[i+1] = frames[i+1]*g + r.fadeOut[2*r.fadePos+1]*(1-g)
+		r.fadePos++
+	}
+}
+
+// SetSource replaces the source from the next output frame.
+//
+// seek is called with the media position of the next output frame, and must make src start from the position.
+// As the frames already generated from the previous source are discarded, the new source continues exactly from the output position.
+func (r *rateStream) SetSource(src io.ReadSeeker, seek func(position time.Duration)) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	media := math.Round(r.mediaFrameAt(r.outPos))
+	seek(time.Duration(int64(media) * int64(time.Second) / int64(r.samplingFrequency)))
+
+	r.src = src
+	r.in = r.in[:0]
+	r.cursor = 0
+	r.out = r.out[:0]
+	r.srcEOF = false
+	r.padding = 0
+	r.resetStretch()
+
+	rate := r.rate
+	if r.scrubLength > 0 {
+		// The position is held while scrubbing.
+		rate = 0
+	}
+	r.anchors = append(r.anchors, rateAnchor{
+		out:   r.outPos,
+		media: media,
+		rate:  rate,
+	})
+	if len(r.anchors) > maxRateAnchors {
+		r.anchors = r.anchors[len(r.anchors)-maxRateAnchors:]
+	}
+}
+
+// Scrub makes the stream play only a snippet of the given duration at the given volume, and then hold the position.
+// Scrub should be called just after Seek. Scrubbing ends at the next Seek.
+func (r *rateStream) Scrub(d time.Duration, volume float64) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.scrubLength = max(int(int64(d)*int64(r.samplingFrequency)/int64(time.Second)), 1)
+	r.scrubPos = 0
+	// The snippet has its own fades.
+	r.fadeOut = r.fadeOut[:0]
+	r.fadePos = 0
+	r.scrubVolume = float32(volume)
+
+	// The position doesn't advance while scrubbing.
+	r.anchors = append(r.anchors[:0], rateAnchor{
+		out:   r.outPos,
+		media: r.mediaFrameAt(r.outPos),
+		rate:  0,
+	})
+}
+
+// applyScrubEnvelope applies the volume and short fades to the snippet frames to avoid clicks.
+func (r *rateStream) applyScrubEnvelope(frames []float32) {
+	fade := max(min(r.samplingFrequency*5/1000, r.scrubLength/2), 1)
+	for i := 0; i < len(frames); i += 2 {
+		k := r.scrubPos
+		g := r.scrubVolume * min(1, float32(k)/float32(fade), float32(r.scrubLength-k)/float32(fade))
+		frames[i] *= g
+		frames[i+1] *= g
+		r.scrubPos++
+	}
+}
+
+// Seek implements io.Seeker. The offset is both in the output and in the source, as seeking resets the rate anchors.
+func (r *rateStream) Seek(offset int64, whence int) (int64, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.outPos * 8
+	default:
+		return 0, fmt.Errorf("webmplayer: whence must be io.SeekStart or io.SeekCurrent for Seek: %d", whence)
+	}
+
+	if offset == r.outPos*8 && whence == io.SeekCurrent {
+		return offset, nil
+	}
+
+	// Align the offset to the frame size.
+	offset = offset / 8 * 8
+
+	if _, err := r.src.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	r.in = r.in[:0]
+	r.cursor = 0
+	r.out = r.out[:0]
+	r.srcEOF = false
+	r.padding = 0
+	r.resetStretch()
+	r.scrubLength = 0
+	r.scrubPos = 0
+	// fadeOut is kept to be mixed into the frames from the new position.
+	r.history = r.history[:0]
+	r.outPos = offset / 8
+	r.anchors = append(r.anchors[:0], rateAnchor{
+		out:   r.outPos,
+		media: float64(r.outPos),
+		rate:  r.rate,
+	})
+	return offset, nil
+}
+
+func (r *rateStream) resetStretch() {
+	r.hasPrev = false
+	clear(r.ola)
+}
+
+// process generates output frames from the source frames.
+func (r *rateStream) process() error {
+	switch {
+	case r.rate == 1:
+		if err := r.fill(int(r.cursor) + r.chunk); err != nil {
+			return err
+		}
+		r.out = append(r.out, r.in[2*int(r.cursor):max(len(r.in)-2*r.padding, 2*int(r.cursor))]...)
+		r.in = r.in[:0]
+		r.cursor = 0
+		r.padding = 0
+		return nil
+
+	case !r.preservePitch:
+		return r.resample()
+
+	default:
+		return r.stretch()
+	}
+}
+
+// resample generates output frames by interpolation in the quality, which changes the pitch.
+func (r *rateStream) resample() error {
+	n := r.chunk
+	margin := r.interp.margin(r.rate)
+	if err := r.fill(int(r.cursor+float64(n)*r.rate) + margin + 1); err != nil {
+		return err
+	}
+	for range n {
+		i := int(r.cursor)
+		left, right := r.interp.interpolate(r.in, i, r.cursor-float64(i), r.rate)
+		r.out = append(r.out, left, right)
+		r.cursor += r.rate
+	}
+	// Keep the frames before the cursor needed for the next interpolation.
+	r.trim(int(r.cursor) - margin + 1)
+	return nil
+}
+
+// stretch generates output frames by WSOLA, which keeps the pitch.
+func (r *rateStream) stretch() error {
+	n := len(r.window)
+	hop := n / 2
+
+	nominal := int(r.cursor)
+	lo := max(nominal-r.tolerance, 0)
+	hi := nominal + r.tolerance
+	need := hi + n
+	if r.hasPrev {
+		need = max(need, r.prevSegment+2*hop)
+	}
+	if err := r.fill(need); err != nil {
+		return err
+	}
+
+	// Find the segment most similar to the natural continuation of the previous segment.
+	seg := nominal
+	if r.hasPrev {
+		natural := r.in[2*(r.prevSegment+hop) : 2*(r.prevSegment+2*hop)]
+		best := math.Inf(-1)
+		for k := lo; k <= hi; k++ {
+			candidate := r.in[2*k : 2*(k+hop)]
+			var corr, energy float64
+			// Compare every other frame for performance.
+			for i := 0; i < len(natural); i += 4 {
+				c := float64(candidate[i] + candidate[i+1])
+				corr += c * float64(natural[i]+natural[i+1])
+				energy += c * c
+			}
+			if energy > 0 {
+				corr /= math.Sqrt(energy)
+			}
+			if corr > best {
+				best = corr
+				seg = k
+			}
+		}
+	}
+
+	for i, w := range r.window {
+		r.ola[2*i] += r.in[2*(seg+i)] * w
+		r.ola[2*i+1] += r.in[2*(seg+i)+1] * w
+	}
+	r.out = append(r.out, r.ola[:2*hop]...)
+	copy(r.ola, r.ola[2*hop:])
+	clear(r.ola[2*hop:])
+
+	r.prevSegment = seg
+	r.hasPrev = true
+	r.cursor += float64(hop) * r.rate
+	r.trim(min(int(r.cursor)-r.tolerance, r.prevSegment+hop))
+	return nil
+}
+
+// fill reads the source until in has at least n frames.
+// After the source ends, in is padded with silence to generate the last frames.
+func (r *rateStream) fill(n int) error {
+	for len(r.in)/2 < n {
+		if r.srcEOF {
+			r.padding += n - len(r.in)/2
+			r.in = append(r.in, make([]float32, 2*n-len(r.in))...)
+			return nil
+		}
+		size := 8 * max(n-len(r.in)/2, r.chunk)
+		if len(r.buf) < size {
+			r.buf = make([]byte, size)
+		}
+		m, err := r.src.Read(r.buf[:size])
+		r.in = append(r.in, unsafe.Slice((*float32)(unsafe.Pointer(unsafe.SliceData(r.buf))), m/4)...)
+		if err == io.EOF {
+			r.srcEOF = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if m == 0 {
+			return io.ErrNoProgress
+		}
+	}
+	return nil
+}
+
+// trim discards the first n frames of in.
+func (r *rateStream) trim(n int) {
+	if n <= 0 {
+		return
+	}
+	r.in = append(r.in[:0], r.in[2*n:]...)
+	r.cursor -= float64(n)
+	r.padding = min(r.padding, len(r.in)/2)
+	r.prevSegment -= n
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"unsafe"
+)
+
+// TestRateStreamStretchAlignsSegments tests that every segment is aligned with the previous one,
+// including after trimming the frames up to the previous segment's continuation.
+func TestRateStreamStretchAlignsSegments(t *testing.T) {
+	const (
+		samplingFrequency = 48000
+		frequency         = 440
+		seconds           = 2
+	)
+	src := make([]float32, 2*samplingFrequency*seconds)
+	for i := range src[:len(src)/2] {
+		v := float32(math.Sin(2 * math.Pi * frequency * float64(i) / samplingFrequency))
+		src[2*i] = v
+		src[2*i+1] = v
+	}
+	for _, rate := range []float64{0.5, 1.5, 2} {
+		data := unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(src))), 4*len(src))
+		r := newRateStream(bytes.NewReader(data), samplingFrequency, ResampleQualityLinear, 0, 0)
+		r.SetRate(rate, true)
+
+		var out []float32
+		for len(out) < samplingFrequency/2*2 {
+			if err := r.process(); err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, r.out...)
+			r.out = r.out[:0]
+		}
+
+		// Without aligning the segments, overlap-adding segments in different phases cancels the sine wave partially.
+		// Check the peak of every period after the first window.
+		period := samplingFrequency / frequency
+		for i := 2 * len(r.window); i+2*period <= len(out); i += 2 * period {
+			var peak float32
+			for _, v := range out[i : i+2*period] {
+				peak = max(peak, v)
+			}
+			if peak < 0.9 {
+				t.Errorf("rate: %v, frame: %d: peak: got: %v, want: >= 0.9", rate, i/2, peak)
+				break
+			}
+		}
+	}
+}
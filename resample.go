@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"math"
+)
+
+// ResampleQuality represents the quality of audio resampling, which is used to change the playback rate without preserving the pitch.
+//
+// A higher quality takes more CPU time. ResampleQualityCubic costs a little more than ResampleQualityLinear,
+// and ResampleQualityPolyphase costs several times as much, more at higher rates as the filter is widened.
+// See the BenchmarkResample benchmarks for the timings on a specific machine.
+type ResampleQuality int
+
+const (
+	// ResampleQualityLinear uses linear interpolation, which is the fastest but causes aliasing and dulls high frequencies.
+	ResampleQualityLinear ResampleQuality = iota
+
+	// ResampleQualityCubic uses cubic (Catmull-Rom) interpolation, which is smoother than linear at a small cost.
+	ResampleQualityCubic
+
+	// ResampleQualityPolyphase uses a windowed sinc filter bank with a low-pass filter for higher rates, which has the least artifacts.
+	ResampleQualityPolyphase
+)
+
+// polyphaseHalfTaps is the number of taps on each side of a polyphase filter when the rate is 1 or lower.
+const polyphaseHalfTaps = 16
+
+// polyphasePhases is the number of phases of a polyphase filter.
+const polyphasePhases = 256
+
+// polyphaseFilter is a windowed sinc filter bank.
+type polyphaseFilter struct {
+	// halfTaps is the number of taps on each side.
+	halfTaps int
+
+	// coeffs is the coefficients of 2*halfTaps taps for each phase.
+	coeffs []float32
+}
+
+// newPolyphaseFilter creates a filter to resample at the given rate.
+//
+// At a rate higher than 1, the cutoff frequency is lowered to avoid aliasing, and the filter is widened accordingly.
+func newPolyphaseFilter(rate float64) *polyphaseFilter {
+	cutoff := min(1, 1/rate)
+	halfTaps := int(math.Ceil(polyphaseHalfTaps / cutoff))
+	taps := 2 * halfTaps
+
+	f := &polyphaseFilter{
+		halfTaps: halfTaps,
+		coeffs:   make([]float32, polyphasePhases*taps),
+	}
+	for p := range polyphasePhases {
+		frac := float64(p) / polyphasePhases
+		cs := f.coeffs[p*taps : (p+1)*taps]
+		var sum float64
+		for k := range cs {
+			// x is the distance from the interpolated position to the tap.
+			x := float64(k-halfTaps+1) - frac
+			v := cutoff * sinc(cutoff*x) * blackman(x/float64(halfTaps))
+			cs[k] = float32(v)
+			sum += v
+		}
+		// Normalize the gain at DC.
+		for k := range cs {
+			cs[k] = float32(float64(cs[k]) / sum)
+		}
+	}
+	return f
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman returns the Blackman window value for x in [-1, 1].
+func blackman(x float64) float64 {
+	if x <= -1 || x >= 1 {
+		return 0
+	}
+	t := math.Pi * (x + 1)
+	return 0.42 - 0.5*math.Cos(t) + 0.08*math.Cos(2*t)
+}
+
+// interpolator interpolates stereo frames in a quality to resample them.
+type interpolator struct {
+	quality ResampleQuality
+
+	// polyphase is the filter for polyphaseRate, which is created lazily.
+	polyphase     *polyphaseFilter
+	polyphaseRate float64
+}
+
+// margin returns the number of frames needed on each side of an interpolated position at the given rate.
+func (p *interpolator) margin(rate float64) int {
+	switch p.quality {
+	case ResampleQualityCubic:
+		return 2
+	case ResampleQualityPolyphase:
+		return p.polyphaseFilter(rate).halfTaps
+	default:
+		return 1
+	}
+}
+
+// polyphaseFilter returns the polyphase filter for the given rate.
+func (p *interpolator) polyphaseFilter(rate float64) *polyphaseFilter {
+	if p.polyphase == nil || p.polyphaseRate != rate {
+		p.polyphase = newPolyphaseFilter(rate)
+		p.polyphaseRate = rate
+	}
+	return p.polyphase
+}
+
+// interpolate returns the stereo frame at the position i+t in the frames in, which are read at the given rate.
+// Frames before the start of in are treated as the first frame.
+func (p *interpolator) interpolate(in []float32, i int, t float64, rate float64) (float32, float32) {
+	at := func(j int) (float32, float32) {
+		j = max(j, 0)
+		return in[2*j], in[2*j+1]
+	}
+
+	switch p.quality {
+	case ResampleQualityCubic:
+		l0, r0 := at(i - 1)
+		l1, r1 := at(i)
+		l2, r2 := at(i + 1)
+		l3, r3 := at(i + 2)
+		t := float32(t)
+		return catmullRom(l0, l1, l2, l3, t), catmullRom(r0, r1, r2, r3, t)
+
+	case ResampleQualityPolyphase:
+		f := p.polyphaseFilter(rate)
+		taps := 2 * f.halfTaps
+		phase := min(int(t*polyphasePhases+0.5), polyphasePhases-1)
+		cs := f.coeffs[phase*taps : (phase+1)*taps]
+		var left, right float32
+		start := i - f.halfTaps + 1
+		for k, c := range cs {
+			l, r := at(start + k)
+			left += l * c
+			right += r * c
+		}
+		return left, right
+
+	default:
+		l0, r0 := at(i)
+		l1, r1 := at(i + 1)
+		t := float32(t)
+		return l0 + (l1-l0)*t, r0 + (r1-r0)*t
+	}
+}
+
+// catmullRom interpolates between p1 and p2 with the Catmull-Rom spline.
+func catmullRom(p0, p1, p2, p3, t float32) float32 {
+	return p1 + 0.5*t*(p2-p0+t*(2*p0-5*p1+4*p2-p3+t*(3*(p1-p2)+p3-p0)))
+}
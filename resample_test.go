@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"unsafe"
+)
+
+// benchmarkResample measures resampling a second of 48 kHz stereo audio at the given rate.
+func benchmarkResample(b *testing.B, quality ResampleQuality, rate float64) {
+	const samplingFrequency = 48000
+	src := make([]float32, 2*samplingFrequency)
+	for i := range src[:len(src)/2] {
+		v := float32(math.Sin(2 * math.Pi * 440 * float64(i) / samplingFrequency))
+		src[2*i] = v
+		src[2*i+1] = v
+	}
+	data := unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(src))), 4*len(src))
+	out := int(samplingFrequency / rate)
+
+	b.ResetTimer()
+	for range b.N {
+		r := newRateStream(bytes.NewReader(data), samplingFrequency, quality, 0, 0)
+		r.SetRate(rate, false)
+		for len(r.out)/2 < out {
+			if err := r.process(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkResampleLinear(b *testing.B) {
+	benchmarkResample(b, ResampleQualityLinear, 1.5)
+}
+
+func BenchmarkResampleCubic(b *testing.B) {
+	benchmarkResample(b, ResampleQualityCubic, 1.5)
+}
+
+func BenchmarkResamplePolyphase(b *testing.B) {
+	benchmarkResample(b, ResampleQualityPolyphase, 1.5)
+}
+
+func BenchmarkResamplePolyphaseRate2(b *testing.B) {
+	benchmarkResample(b, ResampleQualityPolyphase, 2)
+}
@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"unsafe"
+)
+
+// sampleRateStream converts the sampling frequency of a stereo float32 stream by interpolation in a ResampleQuality.
+//
+// As the positions in the same duration are the same in both the frequencies, the positions of the audio player can be used as they are.
+type sampleRateStream struct {
+	src io.ReadSeeker
+
+	// step is the number of source frames per output frame.
+	step float64
+
+	interp interpolator
+
+	// chunk is the minimum number of frames read from src at a time.
+	chunk int
+
+	// in is the source frames not consumed yet.
+	in []float32
+	// cursor is the read position in frames in in.
+	cursor float64
+
+	// eof indicates whether the source has reached the end.
+	eof bool
+
+	// pos is the position in frames of the next output frame.
+	pos int64
+
+	buf []byte
+}
+
+// newSampleRateStream creates a sampleRateStream. chunk is the minimum number of frames read from src at a time, or 0 for samplesPerBuffer.
+func newSampleRateStream(src io.ReadSeeker, srcFrequency, dstFrequency int, quality ResampleQuality, chunk int) *sampleRateStream {
+	if chunk <= 0 {
+		chunk = samplesPerBuffer
+	}
+	return &sampleRateStream{
+		src:    src,
+		step:   float64(srcFrequency) / float64(dstFrequency),
+		interp: interpolator{quality: quality},
+		chunk:  chunk,
+	}
+}
+
+func (s *sampleRateStream) Read(buf []byte) (int, error) {
+	dst := unsafe.Slice((*float32)(unsafe.Pointer(unsafe.SliceData(buf))), len(buf)/8*2)
+	if len(dst) == 0 {
+		return 0, nil
+	}
+
+	n := len(dst) / 2
+	margin := s.interp.margin(s.step)
+	if err := s.fill(int(s.cursor+float64(n)*s.step) + margin + 1); err != nil {
+		return 0, err
+	}
+	// Near the end, generate only the frames that can be interpolated.
+	if available := int((float64(len(s.in)/2-margin) - s.cursor) / s.step); available < n {
+		n = max(available, 0)
+	}
+	if n == 0 {
+		if s.eof {
+			return 0, io.EOF
+		}
+		return 0, nil
+	}
+
+	for i := range n {
+		j := int(s.cursor)
+		dst[2*i], dst[2*i+1] = s.interp.interpolate(s.in, j, s.cursor-float64(j), s.step)
+		s.cursor += s.step
+	}
+
+	// Keep the frames before the cursor needed for the next interpolation.
+	if k := int(s.cursor) - margin + 1; k > 0 {
+		s.in = append(s.in[:0], s.in[2*k:]...)
+		s.cursor -= float64(k)
+	}
+	s.pos += int64(n)
+	return 8 * n, nil
+}
+
+// fill reads the source until in has at least n frames or the source ends.
+func (s *sampleRateStream) fill(n int) error {
+	for len(s.in)/2 < n && !s.eof {
+		size := 8 * max(n-len(s.in)/2, s.chunk)
+		if len(s.buf) < size {
+			s.buf = make([]byte, size)
+		}
+		m, err := s.src.Read(s.buf[:size])
+		s.in = append(s.in, unsafe.Slice((*float32)(unsafe.Pointer(unsafe.SliceData(s.buf))), m/4)...)
+		if err == io.EOF {
+			s.eof = true
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if m == 0 {
+			// The source has no data ready. The available frames are returned.
+			break
+		}
+	}
+	return nil
+}
+
+// Seek implements io.Seeker. The offset is in the output frequency, and is converted to the source frequency.
+func (s *sampleRateStream) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		if offset == 0 {
+			return 8 * s.pos, nil
+		}
+		offset += 8 * s.pos
+	default:
+		return 0, fmt.Errorf("webmplayer: whence must be io.SeekStart or io.SeekCurrent for Seek: %d", whence)
+	}
+
+	frame := offset / 8
+	srcFrame := int64(math.Round(float64(frame) * s.step))
+	if _, err := s.src.Seek(8*srcFrame, io.SeekStart); err != nil {
+		return 0, err
+	}
+	s.in = s.in[:0]
+	s.cursor = 0
+	s.eof = false
+	s.pos = frame
+	return 8 * frame, nil
+}
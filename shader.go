@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// yCbCrShaderSrc is a shader to convert YCbCr planes to RGB.
+//
+// The planes are packed into one source image vertically in the order of Y, Cb, Cr, and optionally alpha.
+// Four 8-bit values are packed into one RGBA pixel, so that the planes can be uploaded without conversion.
+const yCbCrShaderSrc = `//kage:unit pixels
+
+package main
+
+// CbOrigin and CrOrigin are the positions of the Cb and Cr planes in the source image.
+var CbOrigin vec2
+var CrOrigin vec2
+
+// ChromaScale is the ratio of the luma plane size to the chroma plane size.
+var ChromaScale vec2
+
+// YCbCrToRGB is an affine matrix to convert YCbCr to RGB.
+var YCbCrToRGB mat4
+
+// HDRTransfer is the transfer function of HDR frames to tone-map: 0 for none, 1 for PQ and 2 for HLG.
+// HDRPeak is the peak luminance of HDR frames in nits.
+var HDRTransfer float
+var HDRPeak float
+
+// AlphaOrigin is the position of the alpha plane in the source image, which is valid when HasAlpha is not 0.
+var AlphaOrigin vec2
+var HasAlpha float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	p := floor(dstPos.xy - imageDstOrigin())
+	c := floor(p / ChromaScale)
+
+	y := planeAt(p, vec2(0))
+	cb := planeAt(c, CbOrigin)
+	cr := planeAt(c, CrOrigin)
+	rgb := (YCbCrToRGB * vec4(y, cb, cr, 1)).rgb
+	if HDRTransfer != 0 {
+		rgb = toneMap(clamp(rgb, 0, 1))
+	}
+
+	a := 1.0
+	if HasAlpha != 0 {
+		a = planeAt(p, AlphaOrigin)
+	}
+	return vec4(clamp(rgb, 0, 1)*a, a) * color
+}
+
+// sdrWhite is the luminance of the SDR reference white in nits.
+const sdrWhite = 203.0
+
+// toneMapKnee is the luminance relative to the SDR reference white above which HDR luminance is compressed.
+const toneMapKnee = 0.75
+
+// toneMap converts non-linear BT.2020 RGB values of HDR to sRGB values. See also colorSpace.toneMap.
+func toneMap(e vec3) vec3 {
+	lumaCoeffs := vec3(0.2627, 0.6780, 0.0593)
+
+	// Convert the values to the linear light relative to the SDR reference white.
+	var l vec3
+	if HDRTransfer == 1 {
+		p := pow(e, vec3(1/78.84375))
+		l = pow(max(p-0.8359375, vec3(0))/(18.8515625-18.6875*p), vec3(1/0.1593017578125)) * (10000 / sdrWhite)
+	} else {
+		lo := e * e / 3
+		hi := (exp((e-0.55991073)/0.17883277) + 0.28466892) / 12
+		l = mix(lo, hi, step(vec3(0.5), e))
+		ys := dot(l, lumaCoeffs)
+		l *= pow(max(ys, 1e-6), 0.2) * (1000 / sdrWhite)
+	}
+
+	// Compress the luminance above the knee with the extended Reinhard operator.
+	y := dot(l, lumaCoeffs)
+	if y > toneMapKnee {
+		t := (y - toneMapKnee) / (1 - toneMapKnee)
+		tp := (HDRPeak/sdrWhite - toneMapKnee) / (1 - toneMapKnee)
+		t *= (1 + t/(tp*tp)) / (1 + t)
+		l *= (toneMapKnee + (1-toneMapKnee)*t) / y
+	}
+
+	// Convert BT.2020 primaries to BT.709 primaries, and apply the sRGB transfer.
+	l = mat3(1.6605, -0.1246, -0.0182, -0.5876, 1.1329, -0.1006, -0.0728, -0.0083, 1.1187) * l
+	l = clamp(l, 0, 1)
+	return mix(l*12.92, 1.055*pow(l, vec3(1/2.4))-0.055, step(vec3(0.0031308), l))
+}
+
+// planeAt returns the value at p of the plane at origin.
+func planeAt(p vec2, origin vec2) float {
+	t := imageSrc0UnsafeAt(imageSrc0Origin() + origin + vec2(floor(p.x/4), p.y) + 0.5)
+	m := 1 - step(0.5, abs(vec4(0, 1, 2, 3)-mod(p.x, 4)))
+	return dot(t, m)
+}
+`
+
+var (
+	yCbCrShader     *ebiten.Shader
+	yCbCrShaderErr  error
+	yCbCrShaderOnce sync.Once
+)
+
+func ensureYCbCrShader() (*ebiten.Shader, error) {
+	yCbCrShaderOnce.Do(func() {
+		yCbCrShader, yCbCrShaderErr = ebiten.NewShader([]byte(yCbCrShaderSrc))
+	})
+	return yCbCrShader, yCbCrShaderErr
+}
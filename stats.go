@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// VideoQueueState represents the state of the video pipeline, which is useful for editor-style tools.
+type VideoQueueState struct {
+	// PendingPTS is the presentation timestamps of the demuxed video packets waiting to be decoded, in decoding order.
+	// The length is the queue depth.
+	PendingPTS []time.Duration
+
+	// NextPTS is the presentation timestamp of the decoded frame waiting for its time to be shown, or -1 if there is no such frame.
+	NextPTS time.Duration
+
+	// CurrentPTS is the presentation timestamp of the frame currently shown, or -1 if there is no frame yet.
+	CurrentPTS time.Duration
+}
+
+// VideoQueueState returns a snapshot of the state of the video pipeline.
+// The state changes concurrently, so the returned value might be already outdated.
+//
+// VideoQueueState returns nil if there is no video.
+func (p *Player) VideoQueueState() *VideoQueueState {
+	if p.videoStream == nil {
+		return nil
+	}
+	return p.videoStream.QueueState()
+}
+
+// PlaybackStats represents statistics of a player's playback, which are useful for analytics like skip rates or QoE.
+type PlaybackStats struct {
+	// WatchTime is the total duration of the media played, excluding the ranges skipped by seeking.
+	// WatchTime doesn't increase after the player finishes.
+	WatchTime time.Duration
+
+	// Completion is the ratio of the furthest position reached by playing to the duration, in [0, 1].
+	// A position reached only by seeking doesn't count.
+	Completion float64
+
+	// RebufferCount is the number of times the playback had to wait for data after it started, e.g. due to slow media.
+	// Waits just after the start and after seeking are not counted.
+	RebufferCount int
+
+	// RebufferDuration is the total duration of the waits counted in RebufferCount.
+	RebufferDuration time.Duration
+}
+
+// VideoStats represents statistics of video decoding, which are useful for a diagnostics overlay.
+type VideoStats struct {
+	// DecodedFrames is the number of decoded frames, including the frames decoded to seek.
+	DecodedFrames int
+
+	// DroppedFrames is the number of frames not shown as they were late. See also PlayerOptions.FrameDropPolicy.
+	DroppedFrames int
+
+	// CorruptFrames is the number of packets that failed to decode and were skipped. See also PlayerOptions.ErrorResilient.
+	CorruptFrames int
+
+	// AverageDecodeTime is the average time to decode a frame.
+	AverageDecodeTime time.Duration
+
+	// DecodeFPS is the number of frames decoded per second, measured every second.
+	// DecodeFPS is 0 while nothing is decoded, e.g. after the end.
+	DecodeFPS float64
+}
+
+// VideoStats returns the statistics of the video decoding so far.
+//
+// VideoStats returns nil if there is no video.
+func (p *Player) VideoStats() *VideoStats {
+	if p.videoStream == nil {
+		return nil
+	}
+	return p.videoStream.Stats()
+}
+
+// PlaybackStats returns the statistics of the playback so far.
+//
+// PlaybackStats can be called anytime, e.g. when the player is discarded to report the statistics.
+func (p *Player) PlaybackStats() *PlaybackStats {
+	pos := p.playedPosition()
+	watched := p.watchTime
+	furthest := p.furthestPosition
+	if pos > p.watchStart {
+		watched += pos - p.watchStart
+		furthest = max(furthest, pos)
+	}
+
+	stats := &PlaybackStats{
+		WatchTime: watched,
+	}
+	if d := max(p.videoDuration, p.audioDuration); d > 0 {
+		stats.Completion = min(float64(furthest)/float64(d), 1)
+	}
+
+	var stalls []*stallCounter
+	if p.videoStream != nil {
+		stalls = append(stalls, &p.videoStream.stalls)
+	}
+	for _, s := range p.audioTracks {
+		stalls = append(stalls, &s.AudioStream().stalls)
+	}
+	for _, s := range stalls {
+		stats.RebufferCount += int(s.count.Load())
+		stats.RebufferDuration += time.Duration(s.duration.Load())
+	}
+	return stats
+}
+
+// updateWatchTime ends the current continuous playback range at the current position, and starts a new one at next.
+func (p *Player) updateWatchTime(next time.Duration) {
+	if pos := p.playedPosition(); pos > p.watchStart {
+		p.watchTime += pos - p.watchStart
+		p.furthestPosition = max(p.furthestPosition, pos)
+	}
+	p.watchStart = next
+}
+
+// playedPosition returns the current position, which doesn't advance after the player finishes.
+func (p *Player) playedPosition() time.Duration {
+	pos := p.position()
+	if p.ended {
+		return min(pos, p.endPosition)
+	}
+	return pos
+}
+
+// updateEnd records the position where the player finishes. updateEnd is called in Update.
+func (p *Player) updateEnd() {
+	if !p.IsFinished() {
+		p.ended = false
+		return
+	}
+	if !p.ended {
+		p.ended = true
+		p.endPosition = p.position()
+	}
+}
+
+// stallCounter counts waits for packets during playback.
+type stallCounter struct {
+	count    atomic.Int64
+	duration atomic.Int64
+}
+
+// receive receives a packet from ch.
+// If no packet is ready and playing is true, the wait is counted.
+func (s *stallCounter) receive(ch <-chan packet, playing bool) (packet, bool) {
+	if !playing {
+		pkt, ok := <-ch
+		return pkt, ok
+	}
+	select {
+	case pkt, ok := <-ch:
+		return pkt, ok
+	default:
+	}
+	start := time.Now()
+	pkt, ok := <-ch
+	s.count.Add(1)
+	s.duration.Add(int64(time.Since(start)))
+	return pkt, ok
+}
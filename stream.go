@@ -4,73 +4,583 @@
 package webmplayer
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"math"
+	"path/filepath"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/ebml-go/webm"
+	"github.com/hajimehoshi/webmplayer/internal/codec"
+	"github.com/hajimehoshi/webmplayer/internal/demux"
+	"github.com/hajimehoshi/webmplayer/internal/webm"
 )
 
+// packet is a demuxed packet with additional information for decoders.
+type packet struct {
+	webm.Packet
+
+	// epoch is the number of seeks done before the packet is demuxed.
+	// Decoders discard packets with an older epoch than the latest one.
+	epoch int64
+
+	// seek indicates that the packet is a marker of a seek.
+	// Timecode is the target position of the seek.
+	seek bool
+
+	// eos indicates that the packet is a marker of the end of the stream.
+	eos bool
+
+	// err is the error that stopped the demuxer, which is set to the marker of the end of the stream.
+	err error
+}
+
+type seekRequest struct {
+	target time.Duration
+	epoch  int64
+	loop   bool
+}
+
 type stream struct {
 	meta        webm.WebM
 	videoStream *videoStream
 	audioStream *audioStream
 
-	reader *webm.Reader
+	reader demux.Demuxer
+
+	// tracer writes the demuxed packets if not nil. traceID is the stream's serial number for tracer.
+	tracer  *packetTracer
+	traceID int
+
+	loopCount atomic.Int64
+
+	// audioDisabled indicates whether audio packets are not sent to the audio stream.
+	audioDisabled atomic.Bool
+
+	epoch       atomic.Int64
+	seekRequest seekRequest
+	seekCh      chan struct{}
+	seekM       sync.Mutex
+
+	// done is closed when the stream is closed.
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// live indicates whether the stream is a live stream. See demux.LiveDemuxer.
+	// The timecodes of a live stream start at 0 at the first packet, and seeking skips the packets until a keyframe.
+	live bool
+
+	// liveSkipping indicates whether a seek of a live stream is waiting for a keyframe.
+	liveSkipping atomic.Bool
+
+	// liveStart is when the first packet of a live stream was demuxed, and liveDelay is the minimum delay of the packets since then.
+	// They estimate the live edge. liveM protects them.
+	liveStart time.Time
+	liveDelay time.Duration
+	liveM     sync.Mutex
+}
+
+// streamOptions represents options for streams.
+type streamOptions struct {
+	video videoStreamOptions
+
+	// tracer writes the demuxed packets if not nil.
+	tracer *packetTracer
+
+	// warn is called with a recoverable problem if not nil.
+	warn func(err error)
+
+	// audioEOF indicates whether the audio stream returns io.EOF at the end.
+	audioEOF bool
+
+	// profileLabel is the value of the pprof label to identify the player, or an empty string.
+	profileLabel string
 }
 
-func newStream(r io.ReadSeeker) (*stream, error) {
-	s := &stream{}
-	reader, err := webm.Parse(r, &s.meta)
+// newStream creates a stream. If options is nil, the default values are used.
+func newStream(r io.ReadSeeker, options *streamOptions) (*stream, error) {
+	if options == nil {
+		options = &streamOptions{}
+	}
+	s := &stream{
+		seekCh: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		tracer: options.tracer,
+	}
+	if s.tracer != nil {
+		s.traceID = s.tracer.newStreamID()
+	}
+	reader, err := demux.New(r, &s.meta)
 	if err != nil {
 		return nil, err
 	}
 	s.reader = reader
+	if d, ok := reader.(demux.LiveDemuxer); ok {
+		s.live = d.IsLive()
+	}
 
 	vTrack := s.meta.FindFirstVideoTrack()
 	aTrack := s.meta.FindFirstAudioTrack()
 
-	var vPackets chan webm.Packet
-	var aPackets chan webm.Packet
+	labels := profileLabels(r, options.profileLabel)
+
+	var vPackets chan packet
+	var aPackets chan packet
 
 	if vTrack != nil {
-		vPackets = make(chan webm.Packet, 32)
-		s.videoStream, err = newVideoStream(videoCodec(vTrack.CodecID), vPackets)
+		vPackets = make(chan packet, 32)
+		videoOptions := options.video
+		videoOptions.warn = options.warn
+		videoOptions.labels = append(labels[:len(labels):len(labels)], "webmplayer.goroutine", "video", "webmplayer.track", strconv.Itoa(int(vTrack.TrackNumber)))
+		s.videoStream, err = newVideoStream(vTrack, vPackets, &videoOptions)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if aTrack != nil {
-		aPackets = make(chan webm.Packet, 32)
-		s.audioStream, err = newAudioDecoder(audioCodec(aTrack.CodecID), aTrack.CodecPrivate, int(aTrack.Channels), int(aTrack.SamplingFrequency), aPackets)
+		aPackets = make(chan packet, 32)
+		decoder, err := codec.NewAudioDecoder(aTrack, options.warn)
 		if err != nil {
 			return nil, err
 		}
+		s.audioStream = newAudioStream(decoder, aPackets)
+		s.audioStream.endAtEOF = options.audioEOF
+	}
+
+	go pprof.Do(context.Background(), pprof.Labels(append(labels, "webmplayer.goroutine", "demux")...), func(context.Context) {
+		s.loop(vTrack, aTrack, vPackets, aPackets)
+	})
+
+	return s, nil
+}
+
+// profileLabels returns the pprof labels common to the goroutines of a stream of r:
+// "webmplayer.player" with player if not empty, and "webmplayer.file" with the file name if r is a file.
+func profileLabels(r io.Reader, player string) []string {
+	var labels []string
+	if player != "" {
+		labels = append(labels, "webmplayer.player", player)
+	}
+	if f, ok := r.(interface{ Name() string }); ok {
+		labels = append(labels, "webmplayer.file", filepath.Base(f.Name()))
+	}
+	return labels
+}
+
+func (s *stream) loop(vTrack, aTrack *webm.TrackEntry, vPackets, aPackets chan<- packet) {
+	defer func() {
+		if vPackets != nil {
+			close(vPackets)
+		}
+		if aPackets != nil {
+			close(aPackets)
+		}
+		// Drain the packets so that the reader can finish after Close.
+		for range s.reader.Packets() {
+		}
+	}()
+
+	// offset is added to timecodes so that they keep increasing monotonically after looping.
+	var offset time.Duration
+	var lastTimecode time.Duration
+	var epoch int64
+
+	// seeking is the seek waiting for the reader's marker.
+	// Only one seek is sent to the reader at a time, as the reader might merge multiple seeks into one marker.
+	var seeking *seekRequest
+	// pendingSeek is a seek requested while waiting for another seek.
+	var pendingSeek *seekRequest
+
+	// rebased indicates whether offset has been set so that the timecodes of a live stream start at 0.
+	var rebased bool
+	// skipping is the seek of a live stream waiting for a keyframe of skipTrack at or after the target.
+	var skipping *seekRequest
+	skipTrack := vTrack
+	if skipTrack == nil {
+		skipTrack = aTrack
 	}
 
-	go func() {
-		for pkt := range s.reader.Chan {
-			switch {
-			case vTrack == nil:
-				// Audio only.
-				aPackets <- pkt
-			case aTrack == nil:
-				// Video Only.
-				vPackets <- pkt
-			default:
-				switch pkt.TrackNumber {
-				case vTrack.TrackNumber:
-					vPackets <- pkt
-				case aTrack.TrackNumber:
-					aPackets <- pkt
+	send := func(ch chan<- packet, pkt packet) bool {
+		select {
+		case ch <- pkt:
+			return true
+		case <-s.done:
+			return false
+		}
+	}
+	sendMarker := func(pkt packet) bool {
+		pkt.epoch = epoch
+		if vPackets != nil && !send(vPackets, pkt) {
+			return false
+		}
+		if aPackets != nil && !s.audioDisabled.Load() && !send(aPackets, pkt) {
+			return false
+		}
+		return true
+	}
+	sendSeekMarker := func(req *seekRequest) bool {
+		epoch = req.epoch
+		var marker packet
+		marker.Timecode = req.target
+		marker.seek = true
+		return sendMarker(marker)
+	}
+
+	for {
+		var pkt webm.Packet
+		select {
+		case <-s.done:
+			return
+		case <-s.seekCh:
+			s.seekM.Lock()
+			req := s.seekRequest
+			s.seekM.Unlock()
+			if s.live {
+				// A live stream can't be sought by the reader. Skip the packets until a keyframe instead.
+				skipping = &req
+				continue
+			}
+			if seeking != nil {
+				pendingSeek = &req
+				continue
+			}
+			seeking = &req
+			s.reader.Seek(req.target)
+			continue
+		case p, ok := <-s.reader.Packets():
+			if !ok {
+				return
+			}
+			pkt = p
+		}
+
+		if s.tracer != nil {
+			s.tracer.trace(s.traceID, &s.meta, &pkt)
+		}
+
+		// A packet without a track number is a marker sent by the reader after seeking or at the end of the stream.
+		if pkt.TrackNumber == 0 {
+			if pkt.Timecode == webm.BadTC {
+				if seeking != nil {
+					// The reader reached the end before processing the seek.
+					continue
+				}
+				if skipping != nil {
+					// The live stream ended before a keyframe.
+					req := skipping
+					skipping = nil
+					s.liveSkipping.Store(false)
+					if !sendSeekMarker(req) {
+						return
+					}
+				}
+				// A stream stopped by an error doesn't loop.
+				err := s.reader.Err()
+				if err == nil && !s.live && s.consumeLoop() {
+					d := s.Duration()
+					if d <= 0 {
+						d = lastTimecode
+					}
+					offset += d
+					seeking = &seekRequest{
+						loop: true,
+					}
+					s.reader.Seek(0)
+					continue
 				}
+				if !sendMarker(packet{
+					eos: true,
+					err: err,
+				}) {
+					return
+				}
+				continue
+			}
+
+			if seeking == nil {
+				continue
+			}
+			if pendingSeek != nil {
+				seeking = pendingSeek
+				pendingSeek = nil
+				s.reader.Seek(seeking.target)
+				continue
+			}
+			req := seeking
+			seeking = nil
+			if req.loop {
+				continue
+			}
+			offset = 0
+			if !sendSeekMarker(req) {
+				return
+			}
+			continue
+		}
+
+		if seeking != nil {
+			// This packet was demuxed before the seek.
+			pkt.Release()
+			continue
+		}
+
+		if pkt.Timecode != webm.BadTC {
+			if s.live {
+				if !rebased {
+					offset = -pkt.Timecode
+					rebased = true
+				}
+				s.observeLivePacket(pkt.Timecode + offset)
+			}
+			lastTimecode = pkt.Timecode
+			pkt.Timecode += offset
+		}
+
+		if skipping != nil {
+			if pkt.TrackNumber != skipTrack.TrackNumber || !pkt.Keyframe || pkt.Timecode == webm.BadTC || pkt.Timecode < skipping.target {
+				pkt.Release()
+				continue
+			}
+			req := skipping
+			skipping = nil
+			s.liveSkipping.Store(false)
+			if !sendSeekMarker(req) {
+				return
 			}
 		}
-		close(vPackets)
-		close(aPackets)
+
+		// The packet is passed by value, and its data is owned by the receiver, which releases it after decoding.
+		// Only the sent copy is released, so a packet is never released twice.
+		p := packet{
+			Packet: pkt,
+			epoch:  epoch,
+		}
+		// Packets of the other tracks, like the second audio track of a Matroska file, are ignored.
+		switch {
+		case vTrack != nil && pkt.TrackNumber == vTrack.TrackNumber:
+			if !s.videoStream.waitDecodeAhead(p.Timecode, p.epoch, s.done) {
+				return
+			}
+			s.videoStream.enqueue(p.Timecode)
+			if !send(vPackets, p) {
+				return
+			}
+		case aTrack != nil && pkt.TrackNumber == aTrack.TrackNumber && !s.audioDisabled.Load():
+			if !send(aPackets, p) {
+				return
+			}
+		default:
+			p.Release()
+		}
+	}
+}
+
+// consumeLoop reports whether the stream should be played again, and decrements the loop count if needed.
+func (s *stream) consumeLoop() bool {
+	for {
+		n := s.loopCount.Load()
+		if n == 0 {
+			return false
+		}
+		if n < 0 {
+			return true
+		}
+		if s.loopCount.CompareAndSwap(n, n-1) {
+			return true
+		}
+	}
+}
+
+// Close stops demuxing. The decoders finish as their packet channels are closed.
+func (s *stream) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
 		s.reader.Shutdown()
-	}()
+		if s.audioStream != nil {
+			s.audioStream.Close()
+		}
+	})
+}
 
-	return s, nil
+// SetAudioEnabled sets whether the audio packets are sent to the audio stream.
+// A disabled audio stream must be sought before it is enabled again, as packets are missing.
+func (s *stream) SetAudioEnabled(enabled bool) {
+	s.audioDisabled.Store(!enabled)
+}
+
+func (s *stream) SetLoopCount(count int) {
+	s.loopCount.Store(int64(count))
+}
+
+// Seek seeks the stream to the given target position.
+//
+// The reader starts from the keyframe before the target, and the decoders discard the data before the target.
+func (s *stream) Seek(target time.Duration) {
+	s.seekM.Lock()
+	epoch := s.epoch.Add(1)
+	s.seekRequest = seekRequest{
+		target: target,
+		epoch:  epoch,
+	}
+	s.seekM.Unlock()
+
+	if s.videoStream != nil {
+		s.videoStream.Seek(epoch, target)
+	}
+	if s.audioStream != nil {
+		s.audioStream.setEpoch(epoch)
+	}
+
+	if s.live {
+		s.liveSkipping.Store(true)
+	}
+
+	select {
+	case s.seekCh <- struct{}{}:
+	default:
+	}
+}
+
+// IsLive reports whether the stream is a live stream.
+func (s *stream) IsLive() bool {
+	return s.live
+}
+
+// IsLiveSkipping reports whether a seek of a live stream is waiting for a keyframe.
+func (s *stream) IsLiveSkipping() bool {
+	return s.liveSkipping.Load()
+}
+
+// LiveEdge returns the estimated position of the newest data of a live stream.
+// LiveEdge returns false if no packet has been demuxed yet.
+//
+// The live edge is estimated from when the packets are demuxed, assuming that the data arrives in real time at best.
+func (s *stream) LiveEdge() (time.Duration, bool) {
+	s.liveM.Lock()
+	defer s.liveM.Unlock()
+	if s.liveStart.IsZero() {
+		return 0, false
+	}
+	return time.Since(s.liveStart) - s.liveDelay, true
+}
+
+// observeLivePacket updates the estimate of the live edge with a packet demuxed now.
+func (s *stream) observeLivePacket(timecode time.Duration) {
+	s.liveM.Lock()
+	defer s.liveM.Unlock()
+	if s.liveStart.IsZero() {
+		s.liveStart = time.Now()
+		s.liveDelay = -timecode
+		return
+	}
+	s.liveDelay = min(s.liveDelay, time.Since(s.liveStart)-timecode)
+}
+
+// KeyframeBefore returns the position of the last keyframe at or before t.
+// KeyframeBefore returns false if the stream has neither cue points nor an index built by BuildIndex.
+func (s *stream) KeyframeBefore(t time.Duration) (time.Duration, bool) {
+	if d, ok := s.reader.(demux.Indexer); ok {
+		return d.KeyframeBefore(t)
+	}
+	return demux.CueBefore(&s.meta, t)
+}
+
+// BuildIndex indexes the keyframes of a WebM stream without cue points.
+// BuildIndex does nothing for the other streams.
+func (s *stream) BuildIndex(ctx context.Context) error {
+	if d, ok := s.reader.(demux.Indexer); ok {
+		return d.BuildIndex(ctx)
+	}
+	return nil
+}
+
+// Duration returns the duration of the segment.
+//
+// Duration doesn't use webm.SegmentInformation.GetDuration as it truncates the value to seconds.
+func (s *stream) Duration() time.Duration {
+	info := &s.meta.SegmentInformation
+	return time.Duration(info.Duration * float64(info.TimecodeScale))
+}
+
+// AudioGainTag returns the gain in decibels in the GAIN tag for the audio track.
+// AudioGainTag returns false if there is no such tag.
+func (s *stream) AudioGainTag() (float64, bool, error) {
+	track := s.meta.FindFirstAudioTrack()
+	if track == nil {
+		return 0, false, nil
+	}
+	v, ok := s.meta.FindTag(track.TrackUID, "GAIN")
+	if !ok {
+		return 0, false, nil
+	}
+	gain, err := parseDecibels(v)
+	if err != nil {
+		return 0, false, fmt.Errorf("webmplayer: invalid GAIN tag: %q", v)
+	}
+	return gain, true, nil
+}
+
+// r128ReplayGainOffset is the difference in decibels between the reference levels of ReplayGain (-18 LUFS) and EBU R128 (-23 LUFS).
+const r128ReplayGainOffset = 5
+
+// LoudnessGainTag returns the gain in decibels to normalize the loudness of the audio track by the ReplayGain or the R128 tags.
+// The gain is normalized to the ReplayGain's reference level, and is reduced not to clip the peak in the tags if any.
+// LoudnessGainTag returns false if there is no such tag.
+func (s *stream) LoudnessGainTag() (float64, bool, error) {
+	track := s.meta.FindFirstAudioTrack()
+	if track == nil {
+		return 0, false, nil
+	}
+
+	var gain float64
+	if v, ok := s.findTag(track.TrackUID, "REPLAYGAIN_GAIN", "REPLAYGAIN_TRACK_GAIN"); ok {
+		g, err := parseDecibels(v)
+		if err != nil {
+			return 0, false, fmt.Errorf("webmplayer: invalid ReplayGain tag: %q", v)
+		}
+		gain = g
+	} else if v, ok := s.meta.FindTag(track.TrackUID, "R128_TRACK_GAIN"); ok {
+		// The value is a Q7.8 fixed-point number in decibels.
+		q, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, false, fmt.Errorf("webmplayer: invalid R128_TRACK_GAIN tag: %q", v)
+		}
+		gain = float64(q)/256 + r128ReplayGainOffset
+	} else {
+		return 0, false, nil
+	}
+
+	if v, ok := s.findTag(track.TrackUID, "REPLAYGAIN_PEAK", "REPLAYGAIN_TRACK_PEAK"); ok {
+		peak, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("webmplayer: invalid ReplayGain peak tag: %q", v)
+		}
+		if peak > 0 {
+			gain = min(gain, -20*math.Log10(peak))
+		}
+	}
+	return gain, true, nil
+}
+
+// findTag returns the value of the first tag found in names.
+func (s *stream) findTag(trackUID uint64, names ...string) (string, bool) {
+	for _, name := range names {
+		if v, ok := s.meta.FindTag(trackUID, name); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// parseDecibels parses a gain in decibels like "-3.5 dB".
+func parseDecibels(v string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "dB")), 64)
 }
 
 func (s *stream) Meta() *webm.WebM {
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer/internal/webm"
+)
+
+// packetTracer writes the timeline of demuxed packets in CSV. See PlayerOptions.TraceWriter for the format.
+type packetTracer struct {
+	w       io.Writer
+	start   time.Time
+	streams int
+
+	// err is the first error of w. Nothing is written after an error.
+	err error
+
+	m sync.Mutex
+}
+
+func newPacketTracer(w io.Writer) *packetTracer {
+	t := &packetTracer{
+		w:     w,
+		start: time.Now(),
+	}
+	_, t.err = io.WriteString(w, "time,stream,track,type,pts,size,keyframe\n")
+	return t
+}
+
+// newStreamID returns a new serial number for an input stream.
+func (t *packetTracer) newStreamID() int {
+	t.m.Lock()
+	defer t.m.Unlock()
+	id := t.streams
+	t.streams++
+	return id
+}
+
+// trace writes a line for the given packet demuxed from the stream.
+func (t *packetTracer) trace(streamID int, meta *webm.WebM, pkt *webm.Packet) {
+	typ := "other"
+	switch {
+	case pkt.TrackNumber == 0 && pkt.Timecode == webm.BadTC:
+		typ = "eos"
+	case pkt.TrackNumber == 0:
+		typ = "seek"
+	default:
+		for i := range meta.Tracks.TrackEntry {
+			tr := &meta.Tracks.TrackEntry[i]
+			if tr.TrackNumber != pkt.TrackNumber {
+				continue
+			}
+			switch {
+			case tr.IsVideo():
+				typ = "video"
+			case tr.IsAudio():
+				typ = "audio"
+			}
+			break
+		}
+	}
+
+	var pts string
+	if pkt.Timecode != webm.BadTC {
+		pts = strconv.FormatFloat(pkt.Timecode.Seconds(), 'f', 6, 64)
+	}
+	var keyframe int
+	if pkt.Keyframe {
+		keyframe = 1
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.err != nil {
+		return
+	}
+	_, t.err = fmt.Fprintf(t.w, "%.6f,%d,%d,%s,%s,%d,%d\n", time.Since(t.start).Seconds(), streamID, pkt.TrackNumber, typ, pts, len(pkt.Data), keyframe)
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/webmplayer/internal/codec"
+	"github.com/hajimehoshi/webmplayer/internal/libvpx"
+)
+
+// frameDecoder is the decoder used by a video stream, which is either the built-in codec.VideoDecoder or a VideoDecoder.
+type frameDecoder interface {
+	// Decode decodes a packet with its BlockAdditional data, which can be nil.
+	Decode(data []byte, additional []byte) error
+
+	// NextFrame returns the next decoded frame, or nil if there is no more frame.
+	NextFrame(iter *codec.FrameIter) image.Image
+
+	Reset() error
+
+	// ColorSpace returns the bitstream's color space and range of the last frame, if known.
+	ColorSpace() (libvpx.ColorSpace, libvpx.ColorRange)
+
+	Close() error
+}
+
+// externalVideoDecoder adapts a VideoDecoder to frameDecoder.
+// The BlockAdditional data like an alpha channel is ignored, and the color space is taken from the container.
+type externalVideoDecoder struct {
+	decoder VideoDecoder
+
+	// frames is the frames taken from decoder and validated at Decode.
+	frames []image.Image
+}
+
+func (e *externalVideoDecoder) Decode(data []byte, additional []byte) error {
+	clear(e.frames)
+	e.frames = e.frames[:0]
+	if err := e.decoder.Decode(data); err != nil {
+		return err
+	}
+	// Take the frames here to report an invalid frame as an error of Decode.
+	for img := e.decoder.NextFrame(); img != nil; img = e.decoder.NextFrame() {
+		switch img.(type) {
+		case *image.YCbCr, *image.NYCbCrA:
+			e.frames = append(e.frames, img)
+		default:
+			clear(e.frames)
+			e.frames = e.frames[:0]
+			return fmt.Errorf("webmplayer: VideoDecoder.NextFrame must return an *image.YCbCr or an *image.NYCbCrA but %T", img)
+		}
+	}
+	return nil
+}
+
+func (e *externalVideoDecoder) NextFrame(iter *codec.FrameIter) image.Image {
+	if len(e.frames) == 0 {
+		return nil
+	}
+	img := e.frames[0]
+	e.frames[0] = nil
+	e.frames = e.frames[1:]
+	return img
+}
+
+func (e *externalVideoDecoder) Reset() error {
+	clear(e.frames)
+	e.frames = e.frames[:0]
+	return e.decoder.Reset()
+}
+
+func (e *externalVideoDecoder) ColorSpace() (libvpx.ColorSpace, libvpx.ColorRange) {
+	return libvpx.ColorSpaceUnknown, libvpx.ColorRangeStudio
+}
+
+func (e *externalVideoDecoder) Close() error {
+	return e.decoder.Close()
+}
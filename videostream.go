@@ -4,55 +4,244 @@
 package webmplayer
 
 import (
+	"context"
 	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"math"
+	"runtime/pprof"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/ebml-go/webm"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/xlab/libvpx-go/vpx"
+
+	"github.com/hajimehoshi/webmplayer/internal/allocstats"
+	"github.com/hajimehoshi/webmplayer/internal/codec"
+	"github.com/hajimehoshi/webmplayer/internal/webm"
 )
 
+// videoStreamOptions represents options for video streams.
+type videoStreamOptions struct {
+	decoder codec.VideoDecoderOptions
+
+	// lateThreshold is how far behind the current position a frame can be to be shown. 0 means the default.
+	lateThreshold time.Duration
+
+	dropPolicy FrameDropPolicy
+
+	// catchUpFrames is the number of consecutive late frames to start dropping frames until the next keyframe. 0 disables it.
+	catchUpFrames int
+
+	// adaptive indicates whether the decoding work is reduced while frames are late.
+	adaptive bool
+
+	// resilient indicates whether the stream skips corrupt packets instead of stopping.
+	resilient bool
+
+	// maxDecodeAhead is how far ahead of the current position packets can be sent to the decoder. 0 means no limit.
+	maxDecodeAhead time.Duration
+
+	// toneMap indicates whether HDR frames are tone-mapped to SDR.
+	toneMap bool
+
+	// newDecoder creates a decoder to replace the built-in decoder if not nil.
+	newDecoder VideoDecoderFactory
+
+	// sink presents the frames instead of Ebitengine if not nil.
+	sink FrameSink
+
+	// warn is called with a recoverable problem if not nil.
+	warn func(err error)
+
+	// labels is the key-value pairs of the pprof labels of the decoding goroutine.
+	labels []string
+}
+
 type videoStream struct {
-	src   <-chan webm.Packet
-	ctx   *vpx.CodecCtx
-	iface *vpx.CodecIface
+	src        <-chan packet
+	decoder    frameDecoder
+	videoCodec codec.VideoCodec
+
+	// pool is the pool of the frame buffers. A frame is returned to pool when it is no longer referenced.
+	pool *codec.FramePool
+
+	lateThreshold  time.Duration
+	dropPolicy     FrameDropPolicy
+	catchUpFrames  int
+	adaptive       bool
+	resilient      bool
+	maxDecodeAhead time.Duration
+	toneMap        bool
+	sink           FrameSink
+
+	// colour is the track's Colour element.
+	colour webm.Colour
 
+	// cropLeft, cropTop, cropRight and cropBottom are the numbers of pixels to remove from the frame edges.
+	cropLeft   int
+	cropTop    int
+	cropRight  int
+	cropBottom int
+
+	// offscreen, planes and the other GPU resources are used only in the goroutine calling the Player's methods, never in the decoding goroutine.
+	// The decoding goroutine passes frames on the CPU side as frame.
 	offscreen *ebiten.Image
 
+	// imageAccess indicates whether the images are being accessed, to detect concurrent calls.
+	imageAccess atomic.Bool
+
+	// planes is the packed YCbCr (and alpha) planes of the latest frame.
+	planes *ebiten.Image
+
+	// planeBuf, vertices and drawOp are reused to convert frames without allocations.
+	planeBuf []byte
+	vertices []ebiten.Vertex
+	drawOp   ebiten.DrawTrianglesShaderOptions
+
+	// frame is the latest frame that is not converted to offscreen yet.
+	// frame is an *image.YCbCr or an *image.NYCbCrA.
+	frame image.Image
+
+	// currentFrame is the latest frame, which is kept for analysis.
+	currentFrame image.Image
+
+	// colorSpace is the color space of currentFrame.
+	colorSpace colorSpace
+
+	// dominantColor is the cached dominant color of currentFrame, which is valid when dominantColorValid is true.
+	dominantColor      color.RGBA
+	dominantColorValid bool
+
+	// lumaHistogram is the cached luma histogram of currentFrame, or nil if not computed yet.
+	lumaHistogram *LumaHistogram
+
+	// sceneChangeThreshold is the threshold of scene changes in math.Float64bits. 0 disables the detection.
+	sceneChangeThreshold atomic.Uint64
+	// prevLuma is the downscaled luma of the previous frame, which is used only in the decoding goroutine.
+	prevLuma []uint8
+	// luma is a buffer for the downscaled luma of the current frame.
+	luma              []uint8
+	sceneChangeEvents []SceneChangeEvent
+
+	// frameCallback is called with each frame to show in the decoding goroutine.
+	frameCallback atomic.Pointer[func(frame *image.YCbCr, pts time.Duration)]
+
 	pos atomic.Int64
 
-	err atomic.Pointer[error]
+	// rate is the playback rate in math.Float64bits.
+	rate atomic.Uint64
 
-	m sync.Mutex
-}
+	// epoch is the latest epoch of the stream. Packets with an older epoch are discarded.
+	epoch  atomic.Int64
+	seeked chan struct{}
 
-type videoCodec string
+	// paused indicates whether the clock is paused. pauseChanged is notified when paused changes.
+	paused       atomic.Bool
+	pauseChanged chan struct{}
 
-const (
-	videoCodecVP8  videoCodec = "V_VP8"
-	videoCodecVP9  videoCodec = "V_VP9"
-	videoCodecVP10 videoCodec = "V_VP10"
-)
+	err      atomic.Pointer[error]
+	finished atomic.Bool
 
-func newVideoStream(codec videoCodec, src <-chan webm.Packet) (*videoStream, error) {
-	v := &videoStream{
-		src: src,
-		ctx: vpx.NewCodecCtx(),
-	}
-	switch codec {
-	case videoCodecVP8:
-		v.iface = vpx.DecoderIfaceVP8()
-	case videoCodecVP9:
-		v.iface = vpx.DecoderIfaceVP9()
-	default:
-		return nil, fmt.Errorf("webmplayer: unsupported VPX codec: %s", codec)
+	firstFrame     chan struct{}
+	firstFrameOnce sync.Once
+
+	stalls stallCounter
+
+	// aheadBlocked indicates whether the stream is waiting for the maximum decode-ahead duration.
+	aheadBlocked atomic.Bool
+
+	// queue is the timestamps of the packets sent to src and not received yet.
+	queue  []time.Duration
+	queueM sync.Mutex
+
+	// waitingPTS is the timestamp of the decoded frame waiting to be shown, or -1.
+	waitingPTS atomic.Int64
+	// currentPTS is the timestamp of currentFrame.
+	currentPTS time.Duration
+
+	// decodedSize is the size of currentFrame without the cropped edges.
+	decodedSize image.Point
+
+	// shownPTS is the timestamp of the latest shown frame, including a still frame that doesn't replace currentFrame.
+	// shownFrames is the number of the frames shown so far.
+	shownPTS    time.Duration
+	shownFrames int64
+
+	// decodedFrames, droppedFrames, corruptFrames and decodeTime are the statistics of decoding.
+	decodedFrames atomic.Int64
+	droppedFrames atomic.Int64
+	corruptFrames atomic.Int64
+	decodeTime    atomic.Int64
+
+	// decodeFPS is the number of frames decoded per second in math.Float64bits, updated every second.
+	// decodeFPSTime is the time when decodeFPS is updated in Unix nanoseconds.
+	decodeFPS     atomic.Uint64
+	decodeFPSTime atomic.Int64
+	// fpsStart and fpsFrames are used to count frames for decodeFPS in the decoding goroutine.
+	fpsStart  time.Time
+	fpsFrames int
+
+	m sync.Mutex
+}
+
+func newVideoStream(track *webm.TrackEntry, src <-chan packet, options *videoStreamOptions) (*videoStream, error) {
+	if options == nil {
+		options = &videoStreamOptions{}
+	}
+	pool := &codec.FramePool{}
+	var decoder frameDecoder
+	if options.newDecoder != nil {
+		d, err := options.newDecoder(track.CodecID, int(track.Video.PixelWidth), int(track.Video.PixelHeight))
+		if err != nil {
+			if options.warn != nil {
+				options.warn(fmt.Errorf("webmplayer: creating the video decoder failed, and the built-in decoder is used instead: %w", err))
+			}
+		} else if d != nil {
+			decoder = &externalVideoDecoder{decoder: d}
+		}
+	}
+	if decoder == nil {
+		decoderOptions := options.decoder
+		decoderOptions.Pool = pool
+		d, err := codec.NewVideoDecoder(codec.VideoCodec(track.CodecID), &decoderOptions)
+		if err != nil {
+			return nil, err
+		}
+		decoder = d
 	}
-	if err := vpx.Error(vpx.CodecDecInitVer(v.ctx, v.iface, nil, 0, vpx.DecoderABIVersion)); err != nil {
-		return nil, err
+	lateThreshold := options.lateThreshold
+	if lateThreshold == 0 {
+		lateThreshold = time.Second / 60
 	}
-	go v.loop()
+	v := &videoStream{
+		src:            src,
+		decoder:        decoder,
+		videoCodec:     codec.VideoCodec(track.CodecID),
+		pool:           pool,
+		lateThreshold:  lateThreshold,
+		dropPolicy:     options.dropPolicy,
+		catchUpFrames:  options.catchUpFrames,
+		adaptive:       options.adaptive,
+		resilient:      options.resilient,
+		maxDecodeAhead: options.maxDecodeAhead,
+		toneMap:        options.toneMap,
+		sink:           options.sink,
+		colour:         track.Video.Colour,
+		cropLeft:       int(track.PixelCropLeft),
+		cropTop:        int(track.PixelCropTop),
+		cropRight:      int(track.PixelCropRight),
+		cropBottom:     int(track.PixelCropBottom),
+		seeked:         make(chan struct{}, 1),
+		pauseChanged:   make(chan struct{}, 1),
+		firstFrame:     make(chan struct{}),
+	}
+	v.rate.Store(math.Float64bits(1))
+	v.waitingPTS.Store(-1)
+	go pprof.Do(context.Background(), pprof.Labels(options.labels...), func(context.Context) {
+		v.loop()
+	})
 	return v, nil
 }
 
@@ -65,46 +254,751 @@ func (v *videoStream) Update(position time.Duration) error {
 }
 
 func (v *videoStream) Draw(f func(*ebiten.Image)) {
+	v.beginImageAccess()
+	defer v.endImageAccess()
+	if img := v.image(); img != nil {
+		f(img)
+	}
+}
+
+// beginImageAccess marks the start of using the images, and panics if another goroutine is using them.
+//
+// The images must be used in one goroutine at a time, as Ebitengine's single-thread mode and some environments like consoles require.
+func (v *videoStream) beginImageAccess() {
+	if !v.imageAccess.CompareAndSwap(false, true) {
+		panic("webmplayer: the Player's drawing methods and Update must not be called concurrently")
+	}
+}
+
+func (v *videoStream) endImageAccess() {
+	v.imageAccess.Store(false)
+}
+
+// Image returns the latest frame without the cropped edges, or nil if there is no frame yet.
+// The pending frame is converted if needed.
+func (v *videoStream) Image() *ebiten.Image {
+	v.beginImageAccess()
+	defer v.endImageAccess()
+	return v.image()
+}
+
+func (v *videoStream) image() *ebiten.Image {
 	v.m.Lock()
 	defer v.m.Unlock()
+	if v.frame != nil {
+		if err := v.convertFrame(); err != nil {
+			v.err.Store(&err)
+			return nil
+		}
+	}
 	if v.offscreen == nil {
+		return nil
+	}
+	return v.offscreen.SubImage(v.cropped(v.offscreen.Bounds())).(*ebiten.Image)
+}
+
+// cropped returns the given frame bounds without the cropped edges.
+func (v *videoStream) cropped(bounds image.Rectangle) image.Rectangle {
+	return codec.CropBounds(bounds, v.cropLeft, v.cropTop, v.cropRight, v.cropBottom)
+}
+
+// visibleFrame returns the frame without the cropped edges, which shares the pixels with the given frame.
+func (v *videoStream) visibleFrame(img image.Image) image.Image {
+	return codec.SubFrame(img, v.cropped(img.Bounds()))
+}
+
+func (v *videoStream) IsFinished() bool {
+	return v.finished.Load()
+}
+
+// WaitForFirstFrame waits until the first frame is ready to draw.
+func (v *videoStream) WaitForFirstFrame(ctx context.Context) error {
+	t := time.NewTicker(10 * time.Millisecond)
+	defer t.Stop()
+	for {
+		select {
+		case <-v.firstFrame:
+			return nil
+		case <-t.C:
+			if err := v.err.Load(); err != nil {
+				return *err
+			}
+			if v.finished.Load() {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// IsBufferFull reports whether the packet buffer is full or the maximum decode-ahead duration is reached, which blocks the stream from demuxing more packets.
+func (v *videoStream) IsBufferFull() bool {
+	return len(v.src) == cap(v.src) || v.aheadBlocked.Load()
+}
+
+// DominantColor returns the dominant color of the latest frame.
+// DominantColor returns false if there is no frame yet.
+func (v *videoStream) DominantColor() (color.RGBA, bool) {
+	v.m.Lock()
+	defer v.m.Unlock()
+	if v.currentFrame == nil {
+		return color.RGBA{}, false
+	}
+	if !v.dominantColorValid {
+		v.dominantColor = dominantColor(v.visibleFrame(v.currentFrame), v.colorSpace)
+		v.dominantColorValid = true
+	}
+	return v.dominantColor, true
+}
+
+// LumaHistogram returns the luma histogram of the latest frame.
+// LumaHistogram returns nil if there is no frame yet.
+func (v *videoStream) LumaHistogram() *LumaHistogram {
+	v.m.Lock()
+	defer v.m.Unlock()
+	if v.currentFrame == nil {
+		return nil
+	}
+	if v.lumaHistogram == nil {
+		v.lumaHistogram = lumaHistogram(v.visibleFrame(v.currentFrame), v.colorSpace)
+	}
+	return v.lumaHistogram
+}
+
+// Screenshot returns a copy of the latest frame without the cropped edges, or nil if there is no frame yet.
+func (v *videoStream) Screenshot() *image.RGBA {
+	v.m.Lock()
+	defer v.m.Unlock()
+	if v.currentFrame == nil {
+		return nil
+	}
+	return toRGBA(v.visibleFrame(v.currentFrame), v.colorSpace)
+}
+
+// SetSceneChangeThreshold sets the threshold of scene changes. 0 disables the detection.
+func (v *videoStream) SetSceneChangeThreshold(threshold float64) {
+	v.sceneChangeThreshold.Store(math.Float64bits(threshold))
+}
+
+// ShownFrame returns the timestamp of the latest shown frame and the number of the frames shown so far.
+func (v *videoStream) ShownFrame() (time.Duration, int64) {
+	v.m.Lock()
+	defer v.m.Unlock()
+	return v.shownPTS, v.shownFrames
+}
+
+// DecodedSize returns the size of the latest frame without the cropped edges, or zeros if there is no frame yet.
+func (v *videoStream) DecodedSize() image.Point {
+	v.m.Lock()
+	defer v.m.Unlock()
+	return v.decodedSize
+}
+
+// AppendSceneChangeEvents appends the detected scene changes to events, and clears them.
+func (v *videoStream) AppendSceneChangeEvents(events []SceneChangeEvent) []SceneChangeEvent {
+	v.m.Lock()
+	defer v.m.Unlock()
+	events = append(events, v.sceneChangeEvents...)
+	v.sceneChangeEvents = v.sceneChangeEvents[:0]
+	return events
+}
+
+// detectSceneChange compares the frame with the previous frame, and reports whether the scene changes.
+func (v *videoStream) detectSceneChange(img image.Image, pts time.Duration) (SceneChangeEvent, bool) {
+	threshold := math.Float64frombits(v.sceneChangeThreshold.Load())
+	if threshold <= 0 {
+		v.prevLuma = v.prevLuma[:0]
+		return SceneChangeEvent{}, false
+	}
+
+	luma := downscaledLuma(v.visibleFrame(img), v.luma[:0])
+	prev := v.prevLuma
+	v.prevLuma, v.luma = luma, prev
+	if len(prev) == 0 || len(prev) != len(luma) {
+		return SceneChangeEvent{}, false
+	}
+
+	diff := meanAbsDiff(prev, luma)
+	if diff < threshold {
+		return SceneChangeEvent{}, false
+	}
+	return SceneChangeEvent{
+		Position:   pts,
+		Difference: diff,
+	}, true
+}
+
+// SetFrameCallback sets a function called with each frame to show. f can be nil.
+func (v *videoStream) SetFrameCallback(f func(frame *image.YCbCr, pts time.Duration)) {
+	if f == nil {
+		v.frameCallback.Store(nil)
 		return
 	}
-	f(v.offscreen)
+	v.frameCallback.Store(&f)
+}
+
+// SetRate sets the playback rate, which is used to wait for the next frame.
+func (v *videoStream) SetRate(rate float64) {
+	v.rate.Store(math.Float64bits(rate))
+}
+
+// SetPaused sets whether the clock is paused, and the current position.
+// No frame is shown while the clock is paused.
+func (v *videoStream) SetPaused(paused bool, position time.Duration) {
+	v.pos.Store(int64(position))
+	v.paused.Store(paused)
+	select {
+	case v.pauseChanged <- struct{}{}:
+	default:
+	}
+}
+
+// Seek notifies the video stream that the stream seeks to the target position.
+func (v *videoStream) Seek(epoch int64, target time.Duration) {
+	v.epoch.Store(epoch)
+	v.pos.Store(int64(target))
+	v.finished.Store(false)
+	select {
+	case v.seeked <- struct{}{}:
+	default:
+	}
 }
 
 func (v *videoStream) loop() {
-loop:
-	for pkt := range v.src {
-		dataSize := uint32(len(pkt.Data))
-		if err := vpx.Error(vpx.CodecDecode(v.ctx, string(pkt.Data), dataSize, nil, 0)); err != nil {
+	defer v.finished.Store(true)
+	defer func() {
+		if err := v.decoder.Close(); err != nil {
 			v.err.Store(&err)
-			return
 		}
+	}()
+
+	// seekTarget is the target position while seeking, or -1 otherwise.
+	seekTarget := time.Duration(-1)
+	// seekFrame is the last frame before the target position while seeking.
+	var seekFrame image.Image
+	var seekFramePTS time.Duration
+
+	// playing indicates whether a frame has been shown since the start or the latest seek.
+	var playing bool
+	var epoch int64
+
+	// dropping indicates whether packets are dropped until the next keyframe.
+	var dropping bool
+
+	// lateFrames is the number of consecutive late frames.
+	var lateFrames int
+
+	// corrupt indicates whether packets are skipped until the next keyframe after a corrupt packet.
+	var corrupt bool
+
+	var pkt packet
+
+loop:
+	for {
+		// The previous packet's data is no longer used after decoding or skipping it.
+		pkt.Release()
+
+		var ok bool
+		pkt, ok = v.stalls.receive(v.src, playing && !v.paused.Load() && epoch == v.epoch.Load())
+		if !ok {
+			break
+		}
+		if !pkt.seek && !pkt.eos {
+			v.dequeue()
+		}
+		if pkt.epoch < v.epoch.Load() {
+			continue
+		}
+		epoch = pkt.epoch
+		if pkt.seek {
+			playing = false
+			dropping = false
+			lateFrames = 0
+			seekTarget = pkt.Timecode
+			v.pool.Put(seekFrame)
+			seekFrame = nil
+			// A seek is not a scene change.
+			v.prevLuma = v.prevLuma[:0]
+			continue
+		}
+		if pkt.eos {
+			if pkt.err != nil {
+				v.pool.Put(seekFrame)
+				v.err.Store(&pkt.err)
+				return
+			}
+			if seekFrame != nil {
+				v.writeFrame(seekFrame, seekFramePTS)
+				seekFrame = nil
+			}
+			seekTarget = -1
+			playing = false
+			v.finished.Store(true)
+			continue
+		}
+
+		if corrupt {
+			if len(pkt.Data) == 0 || !codec.IsKeyframe(v.videoCodec, pkt.Data[0]) {
+				continue
+			}
+			if err := v.decoder.Reset(); err != nil {
+				v.err.Store(&err)
+				return
+			}
+			corrupt = false
+		}
+
+		if (v.dropPolicy == FrameDropToKeyframe || dropping) && seekTarget < 0 && len(pkt.Data) > 0 {
+			if codec.IsKeyframe(v.videoCodec, pkt.Data[0]) {
+				dropping = false
+			} else if dropping || time.Duration(v.pos.Load())-v.lateThreshold > pkt.Timecode {
+				// A non-keyframe depends on the previous frames, so all the frames until the next keyframe must be dropped.
+				dropping = true
+				v.droppedFrames.Add(1)
+				continue
+			}
+		}
+
+		if v.adaptive && seekTarget < 0 {
+			// While frames are late, skip the late frames that no other frame refers to without decoding them,
+			// and skip the loop filter.
+			if lateFrames > 0 && len(pkt.Data) > 0 && (pkt.Discardable || codec.IsNonReferenceFrame(v.videoCodec, pkt.Data)) &&
+				time.Duration(v.pos.Load())-v.lateThreshold > pkt.Timecode {
+				v.droppedFrames.Add(1)
+				lateFrames++
+				continue
+			}
+			if d, ok := v.decoder.(interface{ SetSkipLoopFilter(skip bool) error }); ok {
+				if err := d.SetSkipLoopFilter(lateFrames > 0); err != nil {
+					v.err.Store(&err)
+					return
+				}
+			}
+		}
+
+		if err := v.decode(pkt); err != nil {
+			if !v.resilient {
+				v.err.Store(&err)
+				return
+			}
+			v.corruptFrames.Add(1)
+			log.Printf("webmplayer: decoding the video packet at %v failed, and the packets until the next keyframe are skipped: %v", pkt.Timecode, err)
+			corrupt = true
+			continue
+		}
+
+		if seekTarget >= 0 {
+			if pkt.Timecode < seekTarget {
+				// Decode frames until the target position, and keep only the last one.
+				var iter codec.FrameIter
+				for img := v.decoder.NextFrame(&iter); img != nil; img = v.decoder.NextFrame(&iter) {
+					v.pool.Put(seekFrame)
+					seekFrame = img
+					seekFramePTS = pkt.Timecode
+				}
+				continue loop
+			}
+			// The frame just before the target position is the one to show at the target position.
+			if seekFrame != nil && pkt.Timecode > seekTarget {
+				v.writeFrame(seekFrame, seekFramePTS)
+			} else {
+				v.pool.Put(seekFrame)
+			}
+			seekTarget = -1
+			seekFrame = nil
+		}
+
 		pos := time.Duration(v.pos.Load())
-		if pos-time.Second/60 > pkt.Timecode {
+		if v.dropPolicy != FrameDropNever && pos-v.lateThreshold > pkt.Timecode {
+			v.droppedFrames.Add(1)
+			lateFrames++
+			if v.catchUpFrames > 0 && lateFrames > v.catchUpFrames {
+				// The decoder cannot keep up. Skip to the next keyframe instead of decoding every late frame.
+				dropping = true
+				lateFrames = 0
+			}
 			continue loop
 		}
+		lateFrames = 0
 
-		var iter vpx.CodecIter
-		for img := vpx.CodecGetFrame(v.ctx, &iter); img != nil; img = vpx.CodecGetFrame(v.ctx, &iter) {
-			img.Deref()
+		var iter codec.FrameIter
+		for img := v.decoder.NextFrame(&iter); img != nil; img = v.decoder.NextFrame(&iter) {
 			if pos < pkt.Timecode {
-				time.Sleep(pkt.Timecode - pos)
+				v.waitingPTS.Store(int64(pkt.Timecode))
+				interrupted := v.wait(pkt.Timecode, pkt.epoch)
+				v.waitingPTS.Store(-1)
+				if interrupted {
+					v.pool.Put(img)
+					continue loop
+				}
 			}
-			// TODO: Use img.ImageYCbCr and a shader.
-			img := img.ImageRGBA()
+			v.writeFrame(img, pkt.Timecode)
+			playing = true
+		}
+	}
+}
 
-			v.m.Lock()
-			if v.offscreen != nil && v.offscreen.Bounds() != img.Bounds() {
-				v.offscreen.Deallocate()
-				v.offscreen = nil
-			}
-			if v.offscreen == nil {
-				v.offscreen = ebiten.NewImage(img.Bounds().Dx(), img.Bounds().Dy())
+// ReleaseResources deallocates the images and the buffers to convert frames.
+// The latest frame is kept, and is converted again when it is drawn next time.
+func (v *videoStream) ReleaseResources() {
+	v.beginImageAccess()
+	defer v.endImageAccess()
+	v.m.Lock()
+	defer v.m.Unlock()
+	v.releaseResources()
+}
+
+func (v *videoStream) releaseResources() {
+	v.releaseConversionResources()
+	if v.offscreen != nil {
+		v.offscreen.Deallocate()
+		v.offscreen = nil
+		v.frame = v.currentFrame
+	}
+}
+
+// ReleaseConversionResources deallocates the buffers to convert frames, while the converted latest frame is kept.
+func (v *videoStream) ReleaseConversionResources() {
+	v.beginImageAccess()
+	defer v.endImageAccess()
+	v.m.Lock()
+	defer v.m.Unlock()
+	v.releaseConversionResources()
+}
+
+func (v *videoStream) releaseConversionResources() {
+	if v.planes != nil {
+		v.planes.Deallocate()
+		v.planes = nil
+	}
+	v.planeBuf = nil
+	v.pool.Clear()
+}
+
+// Close releases all the resources including the latest frame. The stream must not be drawn after Close.
+func (v *videoStream) Close() {
+	v.beginImageAccess()
+	defer v.endImageAccess()
+	v.m.Lock()
+	defer v.m.Unlock()
+	v.releaseResources()
+	v.frame = nil
+	v.currentFrame = nil
+}
+
+// decode decodes the packet and updates the statistics.
+func (v *videoStream) decode(pkt packet) error {
+	start := time.Now()
+	if err := v.decoder.Decode(pkt.Data, pkt.Additional); err != nil {
+		return err
+	}
+	now := time.Now()
+	v.decodedFrames.Add(1)
+	v.decodeTime.Add(int64(now.Sub(start)))
+
+	if v.fpsStart.IsZero() {
+		v.fpsStart = start
+	}
+	v.fpsFrames++
+	if d := now.Sub(v.fpsStart); d >= time.Second {
+		v.decodeFPS.Store(math.Float64bits(float64(v.fpsFrames) / d.Seconds()))
+		v.decodeFPSTime.Store(now.UnixNano())
+		v.fpsStart = now
+		v.fpsFrames = 0
+	}
+	return nil
+}
+
+// Stats returns the statistics of decoding.
+func (v *videoStream) Stats() *VideoStats {
+	stats := &VideoStats{
+		DecodedFrames: int(v.decodedFrames.Load()),
+		DroppedFrames: int(v.droppedFrames.Load()),
+		CorruptFrames: int(v.corruptFrames.Load()),
+	}
+	if stats.DecodedFrames > 0 {
+		stats.AverageDecodeTime = time.Duration(v.decodeTime.Load()) / time.Duration(stats.DecodedFrames)
+	}
+	// The rate is stale if decoding has stopped, e.g. at the end.
+	if time.Since(time.Unix(0, v.decodeFPSTime.Load())) < 2*time.Second {
+		stats.DecodeFPS = math.Float64frombits(v.decodeFPS.Load())
+	}
+	return stats
+}
+
+// enqueue records the timestamp of a packet sent to src.
+func (v *videoStream) enqueue(pts time.Duration) {
+	v.queueM.Lock()
+	defer v.queueM.Unlock()
+	v.queue = append(v.queue, pts)
+}
+
+// dequeue removes the timestamp of a packet received from src.
+func (v *videoStream) dequeue() {
+	v.queueM.Lock()
+	defer v.queueM.Unlock()
+	if len(v.queue) == 0 {
+		return
+	}
+	v.queue = append(v.queue[:0], v.queue[1:]...)
+}
+
+// QueueState returns the state of the frame queue.
+func (v *videoStream) QueueState() *VideoQueueState {
+	state := &VideoQueueState{
+		NextPTS:    time.Duration(v.waitingPTS.Load()),
+		CurrentPTS: -1,
+	}
+
+	v.queueM.Lock()
+	state.PendingPTS = append([]time.Duration(nil), v.queue...)
+	v.queueM.Unlock()
+
+	v.m.Lock()
+	if v.currentFrame != nil {
+		state.CurrentPTS = v.currentPTS
+	}
+	v.m.Unlock()
+	return state
+}
+
+// waitDecodeAhead waits until the packet with the given timestamp and epoch is within the maximum decode-ahead duration from the current position.
+// waitDecodeAhead returns false if done is closed while waiting.
+func (v *videoStream) waitDecodeAhead(pts time.Duration, epoch int64, done <-chan struct{}) bool {
+	if v.maxDecodeAhead <= 0 || pts == webm.BadTC {
+		return true
+	}
+	defer v.aheadBlocked.Store(false)
+	for {
+		// The packet will be discarded after seeking.
+		if epoch < v.epoch.Load() {
+			return true
+		}
+		d := pts - time.Duration(v.pos.Load()) - v.maxDecodeAhead
+		if d <= 0 {
+			return true
+		}
+		v.aheadBlocked.Store(true)
+		// The position is updated only every tick, so poll it instead of waiting for the exact duration.
+		d = time.Duration(float64(d) / math.Float64frombits(v.rate.Load()))
+		t := time.NewTimer(min(d, 10*time.Millisecond))
+		select {
+		case <-t.C:
+		case <-done:
+			t.Stop()
+			return false
+		}
+	}
+}
+
+// wait waits until the clock reaches pts, or until the stream seeks.
+// While the clock is paused, wait waits for the resume, and the remaining time is measured again from the position at the resume.
+// A frame at or before the paused position is not waited for, e.g. the frame at the target of a seek while paused.
+// wait reports whether the wait was interrupted by a seek.
+func (v *videoStream) wait(pts time.Duration, epoch int64) bool {
+	for {
+		d := pts - time.Duration(v.pos.Load())
+		if d <= 0 {
+			return false
+		}
+		// A nil timer channel blocks forever while the clock is paused.
+		var t *time.Timer
+		var c <-chan time.Time
+		if !v.paused.Load() {
+			t = time.NewTimer(time.Duration(float64(d) / math.Float64frombits(v.rate.Load())))
+			c = t.C
+		}
+		var interrupted bool
+		select {
+		case <-c:
+			if !v.paused.Load() {
+				return false
 			}
-			v.offscreen.WritePixels(img.Pix)
-			v.m.Unlock()
+		case <-v.pauseChanged:
+		case <-v.seeked:
+			interrupted = v.epoch.Load() != epoch
+		}
+		if t != nil {
+			t.Stop()
+		}
+		if interrupted {
+			return true
+		}
+	}
+}
+
+// writeFrame sets the frame to show. The frame is converted to RGB at the next Draw.
+//
+// A still frame, which is identical to the current frame, is not converted again.
+// This saves uploads and draws for long static sections like slides.
+func (v *videoStream) writeFrame(img image.Image, pts time.Duration) {
+	if f := v.frameCallback.Load(); f != nil || v.sink != nil {
+		var frame *image.YCbCr
+		switch img := img.(type) {
+		case *image.YCbCr:
+			frame = img
+		case *image.NYCbCrA:
+			frame = &img.YCbCr
+		}
+		frame = frame.SubImage(v.cropped(frame.Rect)).(*image.YCbCr)
+		if f != nil {
+			(*f)(frame, pts)
+		}
+		if v.sink != nil {
+			v.sink.PushFrame(frame, pts)
+		}
+	}
+
+	event, sceneChanged := v.detectSceneChange(img, pts)
+	vcs, vcr := v.decoder.ColorSpace()
+	cs := resolveColorSpace(&v.colour, vcs, vcr, img.Bounds().Dy(), v.toneMap)
+
+	v.m.Lock()
+	defer v.m.Unlock()
+	if sceneChanged {
+		v.sceneChangeEvents = append(v.sceneChangeEvents, event)
+	}
+	v.shownPTS = pts
+	v.shownFrames++
+	// currentFrame is read under the lock, as Close can clear it in another goroutine.
+	if v.currentFrame != nil && v.colorSpace == cs && sameFrame(v.currentFrame, img) {
+		v.pool.Put(img)
+		return
+	}
+	// The previous frame is no longer referenced, as the pending frame is always the current frame.
+	v.pool.Put(v.currentFrame)
+	if v.sink == nil {
+		v.frame = img
+	}
+	v.currentFrame = img
+	v.currentPTS = pts
+	v.decodedSize = v.cropped(img.Bounds()).Size()
+	v.colorSpace = cs
+	v.dominantColorValid = false
+	v.lumaHistogram = nil
+	v.firstFrameOnce.Do(func() {
+		close(v.firstFrame)
+	})
+}
+
+// convertFrame uploads the pending frame's planes and converts them to premultiplied RGBA into offscreen.
+func (v *videoStream) convertFrame() error {
+	var frame *image.YCbCr
+	var alpha []byte
+	var alphaStride int
+	switch f := v.frame.(type) {
+	case *image.YCbCr:
+		frame = f
+	case *image.NYCbCrA:
+		frame = &f.YCbCr
+		alpha = f.A
+		alphaStride = f.AStride
+	}
+	v.frame = nil
+
+	shader, err := ensureYCbCrShader()
+	if err != nil {
+		return err
+	}
+
+	w, h := frame.Rect.Dx(), frame.Rect.Dy()
+	ch := len(frame.Cb) / frame.CStride
+	pw, ph := (max(frame.YStride, alphaStride)+3)/4, h+2*ch
+	if alpha != nil {
+		ph += h
+	}
+	if v.planes != nil && (v.planes.Bounds().Dx() != pw || v.planes.Bounds().Dy() != ph) {
+		v.planes.Deallocate()
+		v.planes = nil
+	}
+	if v.planes == nil {
+		allocstats.Add(allocstats.Upload, 4*pw*ph)
+		v.planes = ebiten.NewImage(pw, ph)
+	}
+	writePlane(v.planes, frame.Y, frame.YStride, 0, h, &v.planeBuf)
+	writePlane(v.planes, frame.Cb, frame.CStride, h, ch, &v.planeBuf)
+	writePlane(v.planes, frame.Cr, frame.CStride, h+ch, ch, &v.planeBuf)
+	if alpha != nil {
+		writePlane(v.planes, alpha, alphaStride, h+2*ch, h, &v.planeBuf)
+	}
+
+	if v.offscreen != nil && (v.offscreen.Bounds().Dx() != w || v.offscreen.Bounds().Dy() != h) {
+		v.offscreen.Deallocate()
+		v.offscreen = nil
+	}
+	if v.offscreen == nil {
+		allocstats.Add(allocstats.Upload, 4*w*h)
+		v.offscreen = ebiten.NewImage(w, h)
+	}
+
+	chromaScaleX, chromaScaleY := 1, 1
+	switch frame.SubsampleRatio {
+	case image.YCbCrSubsampleRatio420:
+		chromaScaleX, chromaScaleY = 2, 2
+	case image.YCbCrSubsampleRatio422:
+		chromaScaleX = 2
+	case image.YCbCrSubsampleRatio440:
+		chromaScaleY = 2
+	}
+
+	if v.vertices == nil {
+		v.vertices = make([]ebiten.Vertex, 4)
+	}
+	vs := v.vertices
+	vs[0] = ebiten.Vertex{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0}
+	vs[1] = ebiten.Vertex{DstX: float32(w), DstY: 0, SrcX: float32(pw), SrcY: 0}
+	vs[2] = ebiten.Vertex{DstX: 0, DstY: float32(h), SrcX: 0, SrcY: float32(ph)}
+	vs[3] = ebiten.Vertex{DstX: float32(w), DstY: float32(h), SrcX: float32(pw), SrcY: float32(ph)}
+	for i := range vs {
+		vs[i].ColorR = 1
+		vs[i].ColorG = 1
+		vs[i].ColorB = 1
+		vs[i].ColorA = 1
+	}
+	m := v.colorSpace.rgbMatrix()
+	op := &v.drawOp
+	op.Images[0] = v.planes
+	if op.Uniforms == nil {
+		op.Uniforms = map[string]any{}
+	}
+	op.Uniforms["CbOrigin"] = []float32{0, float32(h)}
+	op.Uniforms["CrOrigin"] = []float32{0, float32(h + ch)}
+	op.Uniforms["ChromaScale"] = []float32{float32(chromaScaleX), float32(chromaScaleY)}
+	op.Uniforms["YCbCrToRGB"] = m.uniform()
+	op.Uniforms["HDRTransfer"] = float32(v.colorSpace.hdr)
+	op.Uniforms["HDRPeak"] = float32(v.colorSpace.peak)
+	if alpha != nil {
+		op.Uniforms["AlphaOrigin"] = []float32{0, float32(h + 2*ch)}
+		op.Uniforms["HasAlpha"] = float32(1)
+	} else {
+		delete(op.Uniforms, "AlphaOrigin")
+		delete(op.Uniforms, "HasAlpha")
+	}
+	op.Blend = ebiten.BlendCopy
+	v.offscreen.DrawTrianglesShader(vs, quadIndices, shader, op)
+	return nil
+}
+
+// quadIndices is the indices to draw a quad with four vertices.
+var quadIndices = []uint16{0, 1, 2, 1, 2, 3}
+
+// writePlane writes the plane pixels at the given row of dst.
+// Four values are packed into one pixel of dst.
+//
+// buf is a buffer reused to align the rows.
+func writePlane(dst *ebiten.Image, pix []byte, stride int, y int, h int, buf *[]byte) {
+	w := (stride + 3) / 4
+	if stride%4 != 0 {
+		if cap(*buf) < 4*w*h {
+			allocstats.Add(allocstats.Upload, 4*w*h)
+			*buf = make([]byte, 4*w*h)
+		}
+		b := (*buf)[:4*w*h]
+		for j := 0; j < h; j++ {
+			copy(b[4*w*j:], pix[stride*j:stride*(j+1)])
 		}
+		pix = b
 	}
+	dst.SubImage(image.Rect(0, y, w, y+h)).(*ebiten.Image).WritePixels(pix[:4*w*h])
 }
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"image"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/webmplayer/internal/webm"
+)
+
+// fakeVideoDecoder is a VideoDecoder that returns a small frame for each packet.
+type fakeVideoDecoder struct {
+	decoded bool
+
+	// frames is the number of the returned frames.
+	frames atomic.Int64
+}
+
+func (f *fakeVideoDecoder) Decode(data []byte) error {
+	f.decoded = true
+	return nil
+}
+
+func (f *fakeVideoDecoder) NextFrame() image.Image {
+	if !f.decoded {
+		return nil
+	}
+	f.decoded = false
+	f.frames.Add(1)
+	return image.NewYCbCr(image.Rect(0, 0, 16, 16), image.YCbCrSubsampleRatio420)
+}
+
+func (f *fakeVideoDecoder) Reset() error {
+	return nil
+}
+
+func (f *fakeVideoDecoder) Close() error {
+	return nil
+}
+
+// newTestPackets returns a source of count keyframe packets with the given interval, followed by the end of the stream.
+func newTestPackets(count int, interval time.Duration) chan packet {
+	src := make(chan packet, count+1)
+	for i := range count {
+		src <- packet{
+			Packet: webm.Packet{
+				Data:        []byte{0},
+				Timecode:    time.Duration(i) * interval,
+				TrackNumber: 1,
+				Keyframe:    true,
+			},
+		}
+	}
+	src <- packet{
+		eos: true,
+	}
+	return src
+}
+
+func TestResumeAfterLongPauseDoesNotDropFrames(t *testing.T) {
+	const (
+		frameDuration = 40 * time.Millisecond
+		frameCount    = 20
+	)
+
+	track := &webm.TrackEntry{
+		TrackNumber: 1,
+		TrackType:   uint(webm.TrackTypeVideo),
+		CodecID:     "V_VP8",
+	}
+	src := newTestPackets(frameCount, frameDuration)
+
+	v, err := newVideoStream(track, src, &videoStreamOptions{
+		// Tolerate the jitter of the test's clock, which is much shorter than the pause.
+		lateThreshold: 2 * frameDuration,
+		newDecoder: func(codecID string, width, height int) (VideoDecoder, error) {
+			return &fakeVideoDecoder{}, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		close(src)
+		v.Close()
+	}()
+
+	// The player without audio uses the wall clock.
+	p := &Player{
+		videoStream:  v,
+		playbackRate: 1,
+	}
+	update := func() {
+		if err := v.Update(p.position()); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for p.position() < 5*frameDuration {
+		update()
+	}
+
+	// Pause for much longer than the late threshold.
+	p.Pause()
+	update()
+	time.Sleep(20 * frameDuration)
+	p.Play()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !v.IsFinished() {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout")
+		}
+		update()
+	}
+
+	if got := v.Stats().DroppedFrames; got != 0 {
+		t.Errorf("dropped frames: got: %d, want: 0", got)
+	}
+	if _, got := v.ShownFrame(); got != frameCount {
+		t.Errorf("shown frames: got: %d, want: %d", got, frameCount)
+	}
+}
+
+func TestAV1WithVideoDecoderFactory(t *testing.T) {
+	const frameCount = 3
+
+	track := &webm.TrackEntry{
+		TrackNumber: 1,
+		TrackType:   uint(webm.TrackTypeVideo),
+		CodecID:     "V_AV1",
+	}
+	src := newTestPackets(frameCount, 40*time.Millisecond)
+	var codecID string
+	decoder := &fakeVideoDecoder{}
+	v, err := newVideoStream(track, src, &videoStreamOptions{
+		newDecoder: func(id string, width, height int) (VideoDecoder, error) {
+			codecID = id
+			return decoder, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		close(src)
+		v.Close()
+	}()
+	if codecID != "V_AV1" {
+		t.Errorf("codec ID: got: %q, want: %q", codecID, "V_AV1")
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !v.IsFinished() {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout")
+		}
+		if err := v.Update(time.Second); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := decoder.frames.Load(); got != frameCount {
+		t.Errorf("decoded frames: got: %d, want: %d", got, frameCount)
+	}
+}
+
+func TestAV1WithoutVideoDecoderFactory(t *testing.T) {
+	track := &webm.TrackEntry{
+		TrackNumber: 1,
+		TrackType:   uint(webm.TrackTypeVideo),
+		CodecID:     "V_AV1",
+	}
+	src := newTestPackets(0, 0)
+	defer close(src)
+	if _, err := newVideoStream(track, src, nil); err == nil {
+		t.Error("newVideoStream must fail for AV1 without a VideoDecoderFactory")
+	}
+}
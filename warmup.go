@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2024 Hajime Hoshi
+
+package webmplayer
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/webmplayer/internal/codec"
+	"github.com/hajimehoshi/webmplayer/internal/libopus"
+)
+
+// WarmupOptions represents options for Warmup.
+type WarmupOptions struct {
+	// Shader specifies whether the shader to convert video frames is compiled too.
+	// Otherwise, the shader is compiled when the first video frame is drawn.
+	//
+	// The default (zero) value is false.
+	Shader bool
+}
+
+// Warmup initializes the decoders once, so that the first player doesn't cause a hitch, e.g. when the first cutscene starts.
+// Warmup is useful to call during a loading screen.
+//
+// Warmup initializes the VP8, VP9 and Opus decoders. A Vorbis decoder cannot be initialized without the headers of a stream.
+//
+// If options is nil, the default values are used.
+func Warmup(options *WarmupOptions) error {
+	if options == nil {
+		options = &WarmupOptions{}
+	}
+
+	for _, c := range []codec.VideoCodec{codec.VideoCodecVP8, codec.VideoCodecVP9} {
+		d, err := codec.NewVideoDecoder(c, nil)
+		if err != nil {
+			return err
+		}
+		if err := d.Close(); err != nil {
+			return err
+		}
+	}
+
+	d, err := libopus.MSDecoderCreate(48000, 2, 1, 1, []byte{0, 1})
+	if err != nil {
+		return fmt.Errorf("webmplayer: libopus.MSDecoderCreate failed: %w", err)
+	}
+	// A packet with only a TOC byte is decoded by packet loss concealment, which initializes the decoder's internal state.
+	pcm := make([]float32, 2*960)
+	d.DecodeFloat([]byte{0xfc}, pcm, 0)
+	d.Destroy()
+
+	if options.Shader {
+		if _, err := ensureYCbCrShader(); err != nil {
+			return err
+		}
+	}
+	return nil
+}